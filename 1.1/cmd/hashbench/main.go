@@ -0,0 +1,71 @@
+// Команда hashbench измеряет задержку вставки в растущую таблицу.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/polRk/data_structures_and_algorithms/1.1/hashtable"
+)
+
+// putLatency вставляет n строк в таблицу, которая растет автоматически,
+// и возвращает 99-й перцентиль и максимум задержки одной вставки.
+func putLatency(n int) (time.Duration, time.Duration) {
+	t := hashtable.NewTable[string, struct{}](hashtable.FNV1a{})
+	latency := make([]time.Duration, n)
+
+	for i := range latency {
+		key := "key-" + strconv.Itoa(i)
+
+		start := time.Now()
+		t.Put(key, struct{}{})
+		latency[i] = time.Since(start)
+	}
+
+	slices.Sort(latency)
+
+	return latency[n*99/100], latency[n-1]
+}
+
+// parseSizes разбирает список размеров таблиц через запятую.
+func parseSizes(in string) ([]int, error) {
+	var sizes []int
+
+	for _, f := range strings.Split(in, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(f))
+		if err != nil {
+			return nil, err
+		}
+
+		if n <= 0 {
+			return nil, fmt.Errorf("размер таблицы должен быть положительным: %d", n)
+		}
+
+		sizes = append(sizes, n)
+	}
+
+	return sizes, nil
+}
+
+func main() {
+	in := flag.String("n", "10000,100000,1000000", "размеры таблиц через запятую")
+	flag.Parse()
+
+	sizes, err := parseSizes(*in)
+	if err != nil {
+		fmt.Println("Error: ", err)
+		os.Exit(2)
+	}
+
+	fmt.Printf("%-10s\t%15s\t%15s\n", "Вставок", "p99, нс", "Максимум, нс")
+
+	for _, n := range sizes {
+		p99, worst := putLatency(n)
+		fmt.Printf("%-10d\t%15d\t%15d\n", n, p99.Nanoseconds(), worst.Nanoseconds())
+	}
+}
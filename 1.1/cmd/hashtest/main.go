@@ -0,0 +1,129 @@
+// Команда hashtest проверяет качество хэш функций строк:
+// лавинный эффект, независимость бит результата, распределение
+// наборов слов, URL и последовательных идентификаторов по сегментам
+// и скорость вычисления, и выводит отчет.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/polRk/data_structures_and_algorithms/1.1/hashtable"
+	"github.com/polRk/data_structures_and_algorithms/1.1/hashtest"
+)
+
+// function описывает проверяемую хэш функцию.
+type function struct {
+	name string
+	fn   hashtest.Func
+	bits int // Разрядность результата
+}
+
+// functions содержит хэш функции, доступные для проверки.
+var functions = []function{
+	{"pearson8", func(s string) uint { return uint(hashtable.Pearson8Hash(s)) }, 8},
+	{"pearson16", hasher(hashtable.Pearson16), 16},
+	{"pearson32", hasher(hashtable.Pearson32), 32},
+	{"pearson64", hasher(hashtable.Pearson64), 64},
+	{"fnv1a", hasher(hashtable.FNV1a{}), 64},
+	{"seeded", hasher(hashtable.NewSeeded[string]()), 64},
+}
+
+// hasher возвращает хэш функцию таблицы как hashtest.Func.
+func hasher(h hashtable.Hasher[string]) hashtest.Func {
+	return func(s string) uint { return uint(h.Hash(s)) }
+}
+
+// printReport выводит отчет о проверке хэш функции.
+func printReport(r hashtest.Report) {
+	fmt.Printf("== %s (%d бит)\n", r.Name, r.Bits)
+	fmt.Printf("Лавинный эффект: среднее отклонение %.3f, наибольшее %.3f, изменяется бит %.3f\n",
+		r.Avalanche.MeanBias, r.Avalanche.MaxBias, r.Avalanche.MeanFlips)
+	fmt.Printf("Независимость бит: средняя корреляция %.3f, наибольшая %.3f, постоянных бит %d\n",
+		r.Independence.MeanCorrelation, r.Independence.MaxCorrelation, r.Independence.Constant)
+
+	// Столбцы выравниваю по ширине символов, а не байт
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "набор\tключей\tсегментов\tпустых\tцепочка\tколлизий\tравномерность\tнс/ключ\tМБ/с")
+
+	for i, d := range r.Distribution {
+		t := r.Throughput[i]
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\t%d\t%.3f\t%.1f\t%.0f\n",
+			d.Corpus, d.Keys, d.Buckets, d.Empty, d.LongestChain, d.Collisions, d.Uniformity, t.NsPerOp, t.MBPerSecond)
+	}
+
+	w.Flush()
+	fmt.Println()
+}
+
+func main() {
+	names := flag.String("hash", "all", "хэш функции через запятую: pearson8, pearson16, pearson32, pearson64, fnv1a, seeded или all")
+	n := flag.Int("n", 10000, "количество ключей в каждом наборе")
+	samples := flag.Int("samples", 2000, "количество случайных ключей для лавинных проверок")
+	bucketBits := flag.Int("buckets", 0, "разрядность номера сегмента, 0 — по размеру набора")
+	corpus := flag.String("corpus", "", "дополнительный набор ключей: файл со строкой на каждой строке")
+	asJSON := flag.Bool("json", false, "вывести отчет в формате JSON")
+	flag.Parse()
+
+	cfg := hashtest.DefaultConfig(*n)
+	cfg.Samples = *samples
+	cfg.BucketBits = *bucketBits
+
+	if *corpus != "" {
+		f, err := os.Open(*corpus)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+
+		c, err := hashtest.ReadCorpus(filepath.Base(*corpus), f)
+		f.Close()
+
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+
+		cfg.Corpora = append(cfg.Corpora, c)
+	}
+
+	var selected []function
+
+	for _, f := range functions {
+		if *names == "all" || strings.Contains(","+*names+",", ","+f.name+",") {
+			selected = append(selected, f)
+		}
+	}
+
+	if len(selected) == 0 {
+		fmt.Fprintln(os.Stderr, "Неизвестная хэш функция:", *names)
+		os.Exit(2)
+	}
+
+	var reports []hashtest.Report
+
+	for _, f := range selected {
+		r := hashtest.Run(f.name, f.fn, f.bits, cfg)
+
+		if *asJSON {
+			reports = append(reports, r)
+		} else {
+			printReport(r)
+		}
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+
+		if err := enc.Encode(reports); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"strings"
+)
+
+// scriptFlag представляет повторяемый флаг -e с командой сценария.
+type scriptFlag []string
+
+func (f *scriptFlag) String() string {
+	return strings.Join(*f, "; ")
+}
+
+func (f *scriptFlag) Set(v string) error {
+	*f = append(*f, v)
+	return nil
+}
+
+// parseLine разбирает строку сценария на команду и аргумент.
+// Пустые строки и строки, начинающиеся с #, пропускаются.
+func parseLine(line string) (op, arg string, ok bool) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return "", "", false
+	}
+
+	op, arg, _ = strings.Cut(line, " ")
+
+	return op, strings.TrimSpace(arg), true
+}
+
+// batch выполняет команды сценария построчно и выводит
+// результат каждой команды строкой JSON.
+// Номера строк продолжают нумерацию с first.
+// Возвращает номер следующей строки и количество команд, завершившихся ошибкой.
+func batch(t *Table, r io.Reader, w io.Writer, first int) (int, int, error) {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+
+	failed := 0
+	line := first
+
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, 1<<20)
+
+	for ; sc.Scan(); line++ {
+		op, arg, ok := parseLine(sc.Text())
+		if !ok {
+			continue
+		}
+
+		res := execute(t, op, arg)
+		res.Line = line
+
+		if res.Error != "" {
+			failed++
+		}
+
+		if err := enc.Encode(res); err != nil {
+			return line, failed, err
+		}
+	}
+
+	return line, failed, sc.Err()
+}
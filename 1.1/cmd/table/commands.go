@@ -0,0 +1,381 @@
+package main
+
+import (
+	"bufio"
+	"cmp"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/polRk/data_structures_and_algorithms/1.1/filter"
+	"github.com/polRk/data_structures_and_algorithms/1.1/hashtable"
+)
+
+// result представляет результат выполнения команды.
+// В пакетном режиме он выводится строкой JSON.
+type result struct {
+	Line     int                   `json:"line,omitempty"`     // Номер строки сценария
+	Op       string                `json:"op"`                 // Команда
+	Arg      string                `json:"arg,omitempty"`      // Аргумент команды
+	OK       bool                  `json:"ok"`                 // Элемент найден, добавлен или удален
+	Error    string                `json:"error,omitempty"`    // Ошибка выполнения команды
+	Elements []element             `json:"elements,omitempty"` // Элементы таблицы для команды p
+	Stats    *hashtable.Stats      `json:"stats,omitempty"`    // Распределение элементов для команды h
+	Load     *hashtable.LoadResult `json:"load,omitempty"`     // Итоги загрузки для команды load
+	Freq     *frequency            `json:"freq,omitempty"`     // Частоты слов для команды freq
+	FP       *falsePositives       `json:"fp,omitempty"`       // Ложные срабатывания фильтров для команды fp
+}
+
+// frequency представляет результат команды freq.
+type frequency struct {
+	Words    int                          `json:"words"`    // Количество слов в файле
+	Distinct int                          `json:"distinct"` // Количество различных слов
+	Top      []hashtable.KeyCount[string] `json:"top"`      // Самые частые слова
+}
+
+// falsePositives представляет результат команды fp.
+type falsePositives struct {
+	Rate    float64       `json:"rate"`    // Заданная вероятность ложного срабатывания
+	Keys    int           `json:"keys"`    // Количество строк таблицы в фильтрах
+	Probes  int           `json:"probes"`  // Количество проверенных строк, которых нет в таблице
+	Filters []filterRates `json:"filters"` // Результаты фильтров
+}
+
+// filterRates представляет ложные срабатывания одного фильтра.
+type filterRates struct {
+	Name       string  `json:"name"`
+	Expected   float64 `json:"expected"`     // Ожидаемая вероятность ложного срабатывания
+	Measured   float64 `json:"measured"`     // Доля ложных срабатываний на проверенных строках
+	Bytes      int     `json:"bytes"`        // Размер фильтра
+	BitsPerKey float64 `json:"bits_per_key"` // Бит фильтра на строку
+}
+
+// element представляет элемент таблицы в результате команды p.
+type element struct {
+	Segment uint64 `json:"segment"`
+	Key     string `json:"key"`
+}
+
+// command описывает команду программы.
+type command struct {
+	prompt string                            // Приглашение для ввода аргумента, пустое, если аргумент не нужен
+	run    func(t *Table, arg string) result // Выполняет команду
+	show   func(r result)                    // Выводит результат в интерактивном режиме
+}
+
+// prompt содержит приглашение для ввода команды.
+const prompt = "Введите команду (s: Поиск, a: Вставка, d: Удаление, p: Вывод, h: Гистограмма, load: Загрузка из файла, freq: Частоты слов, fp: Ложные срабатывания фильтров): "
+
+// commands содержит команды программы по их именам.
+var commands = map[string]command{
+	"s": {
+		prompt: "Введите строку: ",
+		run: func(t *Table, arg string) result {
+			return result{OK: t.Find(arg) != nil}
+		},
+		show: func(r result) {
+			if r.OK {
+				fmt.Println("Найден элемент: ", r.Arg)
+			} else {
+				fmt.Println("Ничего не найдено.")
+			}
+		},
+	},
+	"a": {
+		prompt: "Введите строку: ",
+		run: func(t *Table, arg string) result {
+			_, found := t.GetOrInsert(arg, struct{}{})
+			return result{OK: !found}
+		},
+		show: func(r result) {
+			fmt.Println("Добавлен элемент: ", r.Arg)
+		},
+	},
+	"d": {
+		prompt: "Введите строку: ",
+		run: func(t *Table, arg string) result {
+			_, ok := t.Delete(arg)
+			return result{OK: ok}
+		},
+		show: func(r result) {
+			if r.OK {
+				fmt.Println("Удален элемент: ", r.Arg)
+			} else {
+				fmt.Println("Ничего не найдено.")
+			}
+		},
+	},
+	"p": {
+		run: func(t *Table, _ string) result {
+			r := result{OK: true, Elements: []element{}}
+			for key, e := range t.Elements() {
+				r.Elements = append(r.Elements, element{Segment: key, Key: e.Key()})
+			}
+
+			return r
+		},
+		show: func(r result) {
+			for i, e := range r.Elements {
+				fmt.Printf("%-3d\tKey: %-3d\tValue: %s\n", i, e.Segment, e.Key)
+			}
+		},
+	},
+	"h": {
+		run: func(t *Table, _ string) result {
+			st := t.Stats()
+			return result{OK: true, Stats: &st}
+		},
+		show: func(r result) {
+			printStats(*r.Stats)
+		},
+	},
+	"freq": {
+		prompt: "Введите имя текстового файла (можно указать количество слов через пробел): ",
+		run: func(t *Table, arg string) result {
+			freq, err := wordFrequency(t, arg)
+			if err != nil {
+				return result{Error: err.Error()}
+			}
+
+			return result{OK: true, Freq: &freq}
+		},
+		show: func(r result) {
+			fmt.Println("Слов:", r.Freq.Words, "различных:", r.Freq.Distinct)
+
+			for i, w := range r.Freq.Top {
+				fmt.Printf("%-3d\t%-8d\t%s\n", i+1, w.Count, w.Key)
+			}
+		},
+	},
+	"fp": {
+		prompt: "Введите вероятность ложного срабатывания (по умолчанию 0.01): ",
+		run: func(t *Table, arg string) result {
+			fp, err := measureFilters(t, arg)
+			if err != nil {
+				return result{Error: err.Error()}
+			}
+
+			return result{OK: true, FP: &fp}
+		},
+		show: func(r result) {
+			fmt.Printf("Строк: %d, проверено отсутствующих: %d, заданная вероятность: %g\n",
+				r.FP.Keys, r.FP.Probes, r.FP.Rate)
+
+			for _, f := range r.FP.Filters {
+				fmt.Printf("%-8s\tожидается: %.5f\tизмерено: %.5f\t%d байт\t%.1f бит на строку\n",
+					f.Name, f.Expected, f.Measured, f.Bytes, f.BitsPerKey)
+			}
+		},
+	},
+	"load": {
+		prompt: "Введите имя файла (для CSV можно указать номер столбца через пробел): ",
+		run: func(t *Table, arg string) result {
+			res, err := load(t, arg)
+			if err != nil {
+				return result{Error: err.Error(), Load: &res}
+			}
+
+			return result{OK: true, Load: &res}
+		},
+		show: func(r result) {
+			fmt.Printf("Добавлено: %d, пропущено повторов: %d, время: %s\n",
+				r.Load.Added, r.Load.Duplicates, r.Load.Elapsed.Round(time.Microsecond))
+		},
+	},
+}
+
+// defaultTop задает количество слов, которые выводит команда freq по умолчанию.
+const defaultTop = 10
+
+// wordFrequency считает слова текстового файла и возвращает самые частые.
+// После имени файла через пробел можно указать количество слов.
+// Слова приводятся к нижнему регистру, разделителями считаются
+// все символы, кроме букв и цифр.
+func wordFrequency(t *Table, arg string) (frequency, error) {
+	path, k := arg, defaultTop
+
+	if i := strings.LastIndex(arg, " "); i >= 0 {
+		if n, err := strconv.Atoi(arg[i+1:]); err == nil && n > 0 {
+			path, k = arg[:i], n
+		}
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return frequency{}, err
+	}
+	defer f.Close()
+
+	c := hashtable.NewCounter(t.Hasher())
+
+	sc := bufio.NewScanner(f)
+	sc.Buffer(nil, 1<<20)
+
+	for sc.Scan() {
+		words := strings.FieldsFunc(sc.Text(), func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})
+
+		for _, w := range words {
+			c.Add(strings.ToLower(w))
+		}
+	}
+
+	if err := sc.Err(); err != nil {
+		return frequency{}, err
+	}
+
+	// Слова с одинаковой частотой упорядочиваю по алфавиту
+	top := c.TopK(k)
+	slices.SortStableFunc(top, func(a, b hashtable.KeyCount[string]) int {
+		return cmp.Or(b.Count-a.Count, strings.Compare(a.Key, b.Key))
+	})
+
+	return frequency{Words: c.Total(), Distinct: c.Len(), Top: top}, nil
+}
+
+// Параметры команды fp.
+const (
+	defaultRate = 0.01   // Вероятность ложного срабатывания по умолчанию
+	probes      = 100000 // Количество проверяемых строк, которых нет в таблице
+)
+
+// measureFilters строит фильтр Блума и кукушкин фильтр по строкам таблицы
+// с хэш функцией таблицы и заданной вероятностью ложного срабатывания
+// и измеряет долю ложных срабатываний на строках, которых нет в таблице.
+func measureFilters(t *Table, arg string) (falsePositives, error) {
+	rate := defaultRate
+
+	if arg != "" {
+		r, err := strconv.ParseFloat(arg, 64)
+		if err != nil || r <= 0 || r >= 1 {
+			return falsePositives{}, fmt.Errorf("неверная вероятность: %s", arg)
+		}
+
+		rate = r
+	}
+
+	if t.Len() == 0 {
+		return falsePositives{}, errors.New("таблица пуста")
+	}
+
+	bloom := filter.NewBloom(t.Hasher(), t.Len(), rate)
+	cuckoo := filter.NewCuckoo(t.Hasher(), t.Len(), rate)
+
+	for key := range t.Keys() {
+		bloom.Add(key)
+
+		if err := cuckoo.Add(key); err != nil {
+			return falsePositives{}, err
+		}
+	}
+
+	// Проверяю строки, которых точно нет в таблице
+	res := falsePositives{Rate: rate, Keys: t.Len()}
+	hits := map[string]int{}
+
+	for i := 0; res.Probes < probes; i++ {
+		key := "fp-probe-" + strconv.Itoa(i)
+		if _, ok := t.Peek(key); ok {
+			continue
+		}
+
+		res.Probes++
+
+		if bloom.Contains(key) {
+			hits["bloom"]++
+		}
+
+		if cuckoo.Contains(key) {
+			hits["cuckoo"]++
+		}
+	}
+
+	res.Filters = []filterRates{
+		{Name: "bloom", Expected: bloom.FalsePositiveRate(), Bytes: bloom.Size()},
+		{Name: "cuckoo", Expected: cuckoo.FalsePositiveRate(), Bytes: cuckoo.Size()},
+	}
+
+	for i := range res.Filters {
+		f := &res.Filters[i]
+		f.Measured = float64(hits[f.Name]) / float64(res.Probes)
+		f.BitsPerKey = float64(f.Bytes*8) / float64(res.Keys)
+	}
+
+	return res, nil
+}
+
+// load загружает в таблицу файл, формат которого определяется расширением:
+// .json — массив строк или объект, .csv — столбец CSV, остальные — строки текста.
+// Для CSV после имени файла через пробел можно указать номер столбца (с нуля).
+func load(t *Table, arg string) (hashtable.LoadResult, error) {
+	path, column := arg, 0
+
+	if i := strings.LastIndex(arg, " "); i >= 0 && strings.EqualFold(filepath.Ext(arg[:i]), ".csv") {
+		n, err := strconv.Atoi(arg[i+1:])
+		if err != nil || n < 0 {
+			return hashtable.LoadResult{}, fmt.Errorf("неверный номер столбца: %s", arg[i+1:])
+		}
+
+		path, column = arg[:i], n
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return hashtable.LoadResult{}, err
+	}
+	defer f.Close()
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return hashtable.LoadJSON[struct{}](t, f)
+	case ".csv":
+		return hashtable.LoadCSV[struct{}](t, f, column)
+	}
+
+	return hashtable.LoadLines[struct{}](t, f)
+}
+
+// execute выполняет команду op с аргументом arg.
+func execute(t *Table, op, arg string) result {
+	cmd, ok := commands[op]
+	if !ok {
+		return result{Op: op, Arg: arg, Error: "неизвестная команда"}
+	}
+
+	r := cmd.run(t, arg)
+	r.Op = op
+	r.Arg = arg
+
+	return r
+}
+
+// printStats выводит на экран сведения о распределении элементов
+// и гистограмму количества элементов в сегментах.
+func printStats(st hashtable.Stats) {
+	fmt.Println("Сегментов:", st.Buckets, "непустых:", st.Segments)
+	fmt.Println("Элементов:", st.Elements, "самый длинный список:", st.LongestChain)
+	fmt.Printf("Коэффициент заполнения: %.3f\n", st.LoadFactor)
+	fmt.Printf("Хи-квадрат: %.3f, равномерность: %.3f\n", st.ChiSquared, st.Uniformity)
+
+	// Длина столбца гистограммы не превышает 50 символов
+	most := 0
+	for _, c := range st.Histogram {
+		most = max(most, c)
+	}
+
+	for n, c := range st.Histogram {
+		bar := 0
+		if most > 0 {
+			bar = (c*50 + most - 1) / most
+		}
+
+		fmt.Printf("%-3d\t%-6d\t%s\n", n, c, strings.Repeat("#", bar))
+	}
+}
@@ -0,0 +1,185 @@
+// Команда table работает с таблицей строк: добавляет, ищет и удаляет строки
+// по командам пользователя, загружает строки из файла, считает повторы
+// и выполняет команды в пакетном режиме.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/polRk/data_structures_and_algorithms/1.1/hashtable"
+)
+
+// Table представляет таблицу строк.
+type Table = hashtable.Table[string, struct{}]
+
+// hashers содержит хэш функции, доступные для выбора флагом -hash.
+var hashers = map[string]func() hashtable.Hasher[string]{
+	"pearson8":  func() hashtable.Hasher[string] { return hashtable.Pearson8 },
+	"pearson16": func() hashtable.Hasher[string] { return hashtable.Pearson16 },
+	"pearson32": func() hashtable.Hasher[string] { return hashtable.Pearson32 },
+	"pearson64": func() hashtable.Hasher[string] { return hashtable.Pearson64 },
+	"fnv1a":     func() hashtable.Hasher[string] { return hashtable.FNV1a{} },
+	"seeded":    func() hashtable.Hasher[string] { return hashtable.NewSeeded[string]() },
+}
+
+// normalizers содержит функции нормализации ключа,
+// доступные для выбора флагом -normalize.
+var normalizers = map[string]func(string) string{
+	"trim": hashtable.TrimSpace,
+	"fold": hashtable.FoldCase,
+	"nfc":  hashtable.NFC,
+	"nfkc": hashtable.NFKC,
+}
+
+// parseNormalizers возвращает функции нормализации
+// по списку имен через запятую.
+func parseNormalizers(list string) ([]func(string) string, error) {
+	var fns []func(string) string
+
+	for name := range strings.SplitSeq(list, ",") {
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		}
+
+		fn, ok := normalizers[name]
+		if !ok {
+			return nil, fmt.Errorf("неизвестная нормализация: %s", name)
+		}
+
+		fns = append(fns, fn)
+	}
+
+	return fns, nil
+}
+
+// readLine читает строку ввода пользователя без пробелов по краям.
+func readLine(in *bufio.Reader) (string, error) {
+	line, err := in.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", err
+	}
+
+	return strings.TrimSpace(line), nil
+}
+
+// interactive обрабатывает команды пользователя, пока не закончится ввод.
+// Аргумент команды можно ввести в той же строке через пробел,
+// иначе программа запросит его отдельно.
+func interactive(t *Table, in *bufio.Reader) error {
+	for {
+		fmt.Print(prompt)
+
+		line, err := readLine(in)
+		if err != nil {
+			return err
+		}
+
+		op, arg, hasArg := strings.Cut(line, " ")
+		if op == "" {
+			continue
+		}
+
+		cmd, ok := commands[op]
+		if !ok {
+			fmt.Println("Неизвестная команда:", op)
+			continue
+		}
+
+		if cmd.prompt != "" && !hasArg {
+			fmt.Print(cmd.prompt)
+
+			if arg, err = readLine(in); err != nil {
+				return err
+			}
+		}
+
+		r := execute(t, op, strings.TrimSpace(arg))
+		if r.Error != "" {
+			fmt.Println("Ошибка:", r.Error)
+			continue
+		}
+
+		cmd.show(r)
+	}
+}
+
+// runBatch выполняет команды флагов -e, а затем сценария script
+// ("-" означает стандартный ввод).
+// Возвращает код завершения программы.
+func runBatch(t *Table, exprs []string, script string) int {
+	out := bufio.NewWriter(os.Stdout)
+	defer out.Flush()
+
+	line, failed, err := batch(t, strings.NewReader(strings.Join(exprs, "\n")), out, 1)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error: ", err)
+		return 2
+	}
+
+	if script != "" {
+		in := io.Reader(os.Stdin)
+
+		if script != "-" {
+			f, err := os.Open(script)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "Error: ", err)
+				return 2
+			}
+			defer f.Close()
+
+			in = f
+		}
+
+		n := 0
+		if _, n, err = batch(t, in, out, line); err != nil {
+			fmt.Fprintln(os.Stderr, "Error: ", err)
+			return 2
+		}
+
+		failed += n
+	}
+
+	if failed > 0 {
+		return 1
+	}
+
+	return 0
+}
+
+func main() {
+	var exprs scriptFlag
+
+	name := flag.String("hash", "pearson8", "хэш функция: pearson8, pearson16, pearson32, pearson64, fnv1a, seeded")
+	script := flag.String("script", "", "файл сценария с командой на каждой строке, - для стандартного ввода")
+	normalize := flag.String("normalize", "", "нормализация ключей через запятую: trim, fold, nfc, nfkc")
+	flag.Var(&exprs, "e", "команда сценария, например \"a строка\" (флаг можно повторять)")
+	flag.Parse()
+
+	hasher, ok := hashers[*name]
+	if !ok {
+		fmt.Fprintln(os.Stderr, "Неизвестная хэш функция:", *name)
+		os.Exit(2)
+	}
+
+	fns, err := parseNormalizers(*normalize)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	table := hashtable.NewTable[string, struct{}](hasher(), hashtable.WithNormalizer[string, struct{}](fns...))
+
+	// Если передан сценарий, то выполняю его без диалога
+	if *script != "" || len(exprs) > 0 {
+		os.Exit(runBatch(table, exprs, *script))
+	}
+
+	if err := interactive(table, bufio.NewReader(os.Stdin)); err != nil && err != io.EOF {
+		fmt.Println(err)
+	}
+}
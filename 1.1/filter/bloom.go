@@ -0,0 +1,155 @@
+package filter
+
+import (
+	"encoding/binary"
+	"math"
+
+	"github.com/polRk/data_structures_and_algorithms/1.1/hashtable"
+)
+
+// Bloom представляет фильтр Блума: массив из m бит, в котором
+// каждая строка устанавливает k бит, выбранных двойным хэшированием.
+// Строки нельзя удалить из фильтра.
+type Bloom struct {
+	bits []uint64
+	m    uint64 // Количество бит
+	k    int    // Количество бит на строку
+	n    int    // Количество добавленных строк
+	hash hashtable.Hasher[string]
+}
+
+// bloomMagic начинает двоичный вид фильтра Блума:
+//
+//	magic    [4]byte "BLMF"
+//	version  uint8
+//	hashBits uint8   разрядность хэш функции
+//	m, k, n  uvarint
+//	биты     по 8 байт, little endian
+//	checksum uint32  CRC-32 (Castagnoli) всех предыдущих байт, big endian
+const bloomMagic = "BLMF"
+
+// NewBloom возвращает пустой фильтр Блума, рассчитанный на expected строк
+// с вероятностью ложного срабатывания rate.
+func NewBloom(hash hashtable.Hasher[string], expected int, rate float64) *Bloom {
+	expected = max(expected, 1)
+	rate = min(max(rate, 1e-9), 0.5)
+
+	// m = -n ln p / (ln 2)^2, k = m/n ln 2 = -log2 p.
+	// k считаю по вероятности, а не по m: m округляется вверх
+	// до целых слов, и k = m/n ln 2 для малого n оказалось бы
+	// слишком большим
+	m := uint64(math.Ceil(-float64(expected) * math.Log(rate) / (math.Ln2 * math.Ln2)))
+	m = (max(m, 1) + 63) / 64 * 64
+	k := max(int(math.Round(-math.Log2(rate))), 1)
+
+	return &Bloom{
+		bits: make([]uint64, (m+63)/64),
+		m:    m,
+		k:    k,
+		hash: hash,
+	}
+}
+
+// Add добавляет строку в фильтр.
+func (b *Bloom) Add(key string) {
+	h1, h2 := b.probe(key)
+
+	for i := range b.k {
+		j := (h1 + uint64(i)*h2) % b.m
+		b.bits[j/64] |= 1 << (j % 64)
+	}
+
+	b.n++
+}
+
+// Contains возвращает false, если строки точно нет в фильтре,
+// и true, если она возможно есть.
+func (b *Bloom) Contains(key string) bool {
+	h1, h2 := b.probe(key)
+
+	for i := range b.k {
+		j := (h1 + uint64(i)*h2) % b.m
+		if b.bits[j/64]&(1<<(j%64)) == 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// probe возвращает первый бит строки и шаг, с которым выбираются
+// следующие биты. Шаг нечетный: при четном шаге и четном m
+// строка попадала бы только в часть бит массива.
+func (b *Bloom) probe(key string) (uint64, uint64) {
+	h1, h2 := split(b.hash, key)
+	return h1, h2 | 1
+}
+
+// Len возвращает количество добавленных строк.
+func (b *Bloom) Len() int {
+	return b.n
+}
+
+// Size возвращает размер массива бит в байтах.
+func (b *Bloom) Size() int {
+	return len(b.bits) * 8
+}
+
+// Hashes возвращает количество бит, которые устанавливает одна строка.
+func (b *Bloom) Hashes() int {
+	return b.k
+}
+
+// FalsePositiveRate возвращает ожидаемую вероятность ложного срабатывания
+// при текущем количестве строк: (1 - e^(-kn/m))^k.
+func (b *Bloom) FalsePositiveRate() float64 {
+	return math.Pow(1-math.Exp(-float64(b.k)*float64(b.n)/float64(b.m)), float64(b.k))
+}
+
+// MarshalBinary возвращает фильтр в двоичном виде.
+// Хэш функция не сохраняется.
+func (b *Bloom) MarshalBinary() ([]byte, error) {
+	buf := make([]byte, 0, len(bloomMagic)+2+3*binary.MaxVarintLen64+len(b.bits)*8+4)
+
+	buf = appendHeader(buf, bloomMagic, b.hash)
+	buf = binary.AppendUvarint(buf, b.m)
+	buf = binary.AppendUvarint(buf, uint64(b.k))
+	buf = binary.AppendUvarint(buf, uint64(b.n))
+
+	for _, w := range b.bits {
+		buf = binary.LittleEndian.AppendUint64(buf, w)
+	}
+
+	return appendChecksum(buf), nil
+}
+
+// UnmarshalBinary заменяет содержимое фильтра фильтром, записанным MarshalBinary.
+// Фильтр должен использовать ту же хэш функцию, с которой был записан.
+// Если данные повреждены, то фильтр не изменяется.
+func (b *Bloom) UnmarshalBinary(data []byte) error {
+	body, err := readHeader(data, bloomMagic, b.hash)
+	if err != nil {
+		return err
+	}
+
+	r := &reader{data: body}
+	m, k, n := r.uvarint(), r.uvarint(), r.uvarint()
+
+	if r.err != nil {
+		return r.err
+	}
+
+	words := (m + 63) / 64
+	if m == 0 || k == 0 || k > 64 || uint64(len(r.data)) != words*8 {
+		return ErrFormat
+	}
+
+	bits := make([]uint64, words)
+	for i := range bits {
+		bits[i] = binary.LittleEndian.Uint64(r.data[i*8:])
+	}
+
+	b.bits, b.m, b.k, b.n = bits, m, int(k), int(n)
+
+	return nil
+}
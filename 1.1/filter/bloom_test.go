@@ -0,0 +1,65 @@
+package filter
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/polRk/data_structures_and_algorithms/1.1/hashtable"
+)
+
+func TestBloomFalsePositiveRate(t *testing.T) {
+	tests := []struct {
+		hash     hashtable.Hasher[string]
+		expected int
+		rate     float64
+		k        int
+	}{
+		// Для малого n массив округляется до 64 бит,
+		// и число бит на строку не должно от этого расти
+		{hashtable.Pearson8, 1, 0.01, 7},
+		{hashtable.FNV1a{}, 10, 0.01, 7},
+		{hashtable.Pearson8, 1000, 0.01, 7},
+		{hashtable.FNV1a{}, 1000, 0.01, 7},
+		{hashtable.FNV1a{}, 5000, 0.001, 10},
+	}
+
+	const queries = 100000
+
+	for _, tt := range tests {
+		name := strconv.Itoa(tt.hash.Bits()) + "/" + strconv.Itoa(tt.expected)
+
+		t.Run(name, func(t *testing.T) {
+			b := NewBloom(tt.hash, tt.expected, tt.rate)
+
+			if b.Hashes() != tt.k {
+				t.Errorf("Hashes() = %d, want %d", b.Hashes(), tt.k)
+			}
+
+			for i := range tt.expected {
+				b.Add("key-" + strconv.Itoa(i))
+			}
+
+			for i := range tt.expected {
+				if key := "key-" + strconv.Itoa(i); !b.Contains(key) {
+					t.Fatalf("Contains(%q) = false после Add", key)
+				}
+			}
+
+			fp := 0
+			for i := range queries {
+				if b.Contains("missing-" + strconv.Itoa(i)) {
+					fp++
+				}
+			}
+
+			// Допускаю погрешность измерения в полтора раза
+			if got := float64(fp) / queries; got > tt.rate*1.5 {
+				t.Errorf("доля ложных срабатываний %.4f, want не больше %.4f", got, tt.rate)
+			}
+
+			if got := b.FalsePositiveRate(); got > tt.rate*1.5 {
+				t.Errorf("FalsePositiveRate() = %.4f, want не больше %.4f", got, tt.rate)
+			}
+		})
+	}
+}
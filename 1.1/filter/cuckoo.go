@@ -0,0 +1,254 @@
+package filter
+
+import (
+	"encoding/binary"
+	"math"
+	"math/bits"
+	"math/rand/v2"
+
+	"github.com/polRk/data_structures_and_algorithms/1.1/hashtable"
+)
+
+// bucketSize задает количество отпечатков в корзине кукушкиного фильтра.
+const bucketSize = 4
+
+// maxKicks ограничивает количество вытеснений при добавлении строки.
+const maxKicks = 500
+
+// Cuckoo представляет кукушкин фильтр: таблицу корзин по 4 отпечатка.
+// Отпечаток строки хранится в одной из двух корзин, вторая корзина
+// вычисляется по первой и отпечатку, поэтому при заполнении
+// отпечатки можно переносить, как в кукушкином хэшировании.
+// В отличие от фильтра Блума строки можно удалять.
+type Cuckoo struct {
+	slots []uint16 // Отпечатки, 0 для свободной ячейки
+	mask  uint64   // Маска номера корзины
+	fp    int      // Разрядность отпечатка
+	n     int      // Количество строк в фильтре
+	hash  hashtable.Hasher[string]
+	rng   *rand.Rand
+}
+
+// cuckooMagic начинает двоичный вид кукушкиного фильтра:
+//
+//	magic    [4]byte "CKOF"
+//	version  uint8
+//	hashBits uint8   разрядность хэш функции
+//	fp       uvarint разрядность отпечатка
+//	buckets  uvarint количество корзин
+//	n        uvarint количество строк
+//	отпечатки по 2 байта, little endian
+//	checksum uint32  CRC-32 (Castagnoli) всех предыдущих байт, big endian
+const cuckooMagic = "CKOF"
+
+// NewCuckoo возвращает пустой кукушкин фильтр, рассчитанный на expected строк
+// с вероятностью ложного срабатывания rate.
+// Разрядность отпечатка ограничена 16 битами,
+// поэтому вероятность не бывает меньше примерно 1e-4.
+func NewCuckoo(hash hashtable.Hasher[string], expected int, rate float64) *Cuckoo {
+	expected = max(expected, 1)
+	rate = min(max(rate, 1e-9), 0.5)
+
+	// Ложное срабатывание возможно в 2 корзинах по 4 отпечатка:
+	// p ≈ 8 / 2^f, отсюда f = log2(8/p)
+	fp := int(math.Ceil(math.Log2(2 * bucketSize / rate)))
+	fp = min(max(fp, 4), 16)
+
+	// Фильтр заполняется примерно на 95%
+	buckets := uint64(1) << bits.Len64(uint64(float64(expected)/bucketSize/0.95))
+
+	return &Cuckoo{
+		slots: make([]uint16, buckets*bucketSize),
+		mask:  buckets - 1,
+		fp:    fp,
+		hash:  hash,
+		rng:   rand.New(rand.NewPCG(buckets, uint64(fp))),
+	}
+}
+
+// Add добавляет строку в фильтр.
+// Если места нет, то возвращает ErrFull, и фильтр не изменяется.
+// Повторное добавление строки добавляет еще один отпечаток.
+func (c *Cuckoo) Add(key string) error {
+	i1, f := c.index(key)
+	i2 := c.alt(i1, f)
+
+	if c.put(i1, f) || c.put(i2, f) {
+		c.n++
+		return nil
+	}
+
+	// Вытесняю случайные отпечатки в их другие корзины,
+	// запоминая замены, чтобы отменить их при неудаче
+	type kick struct {
+		slot uint64
+		old  uint16
+	}
+
+	var kicks []kick
+
+	i := i1
+	if c.rng.IntN(2) == 1 {
+		i = i2
+	}
+
+	for range maxKicks {
+		s := i*bucketSize + uint64(c.rng.IntN(bucketSize))
+		kicks = append(kicks, kick{slot: s, old: c.slots[s]})
+
+		f, c.slots[s] = c.slots[s], f
+		i = c.alt(i, f)
+
+		if c.put(i, f) {
+			c.n++
+			return nil
+		}
+	}
+
+	for j := len(kicks) - 1; j >= 0; j-- {
+		c.slots[kicks[j].slot] = kicks[j].old
+	}
+
+	return ErrFull
+}
+
+// Contains возвращает false, если строки точно нет в фильтре,
+// и true, если она возможно есть.
+func (c *Cuckoo) Contains(key string) bool {
+	i1, f := c.index(key)
+	return c.find(i1, f) >= 0 || c.find(c.alt(i1, f), f) >= 0
+}
+
+// Delete удаляет один отпечаток строки.
+// Удалять можно только добавленные строки, иначе может
+// удалиться отпечаток другой строки с тем же отпечатком.
+// Возвращает false, если отпечатка нет.
+func (c *Cuckoo) Delete(key string) bool {
+	i1, f := c.index(key)
+
+	s := c.find(i1, f)
+	if s < 0 {
+		s = c.find(c.alt(i1, f), f)
+	}
+
+	if s < 0 {
+		return false
+	}
+
+	c.slots[s] = 0
+	c.n--
+
+	return true
+}
+
+// Len возвращает количество строк в фильтре.
+func (c *Cuckoo) Len() int {
+	return c.n
+}
+
+// Size возвращает размер таблицы отпечатков в байтах.
+// Отпечатки хранятся по 2 байта независимо от разрядности.
+func (c *Cuckoo) Size() int {
+	return len(c.slots) * 2
+}
+
+// FingerprintBits возвращает разрядность отпечатка.
+func (c *Cuckoo) FingerprintBits() int {
+	return c.fp
+}
+
+// FalsePositiveRate возвращает ожидаемую вероятность ложного срабатывания
+// при текущей заполненности: каждая из 2*4 ячеек корзин строки
+// занята с вероятностью заполнения и совпадает с вероятностью 1/(2^f-1).
+func (c *Cuckoo) FalsePositiveRate() float64 {
+	load := float64(c.n) / float64(len(c.slots))
+	return 1 - math.Pow(1-1/float64(uint64(1)<<c.fp-1), 2*bucketSize*load)
+}
+
+// index возвращает первую корзину и отпечаток строки.
+// Отпечаток не бывает нулевым.
+func (c *Cuckoo) index(key string) (uint64, uint16) {
+	h1, h2 := split(c.hash, key)
+	f := uint16(h2%(1<<c.fp-1)) + 1
+
+	return h1 & c.mask, f
+}
+
+// alt возвращает другую корзину отпечатка, находящегося в корзине i.
+// alt(alt(i, f), f) == i.
+func (c *Cuckoo) alt(i uint64, f uint16) uint64 {
+	return (i ^ mix(uint64(f))) & c.mask
+}
+
+// put записывает отпечаток в свободную ячейку корзины.
+// Возвращает false, если свободной ячейки нет.
+func (c *Cuckoo) put(i uint64, f uint16) bool {
+	for s := i * bucketSize; s < (i+1)*bucketSize; s++ {
+		if c.slots[s] == 0 {
+			c.slots[s] = f
+			return true
+		}
+	}
+
+	return false
+}
+
+// find возвращает ячейку корзины с отпечатком или -1.
+func (c *Cuckoo) find(i uint64, f uint16) int {
+	for s := i * bucketSize; s < (i+1)*bucketSize; s++ {
+		if c.slots[s] == f {
+			return int(s)
+		}
+	}
+
+	return -1
+}
+
+// MarshalBinary возвращает фильтр в двоичном виде.
+// Хэш функция не сохраняется.
+func (c *Cuckoo) MarshalBinary() ([]byte, error) {
+	buf := make([]byte, 0, len(cuckooMagic)+2+3*binary.MaxVarintLen64+len(c.slots)*2+4)
+
+	buf = appendHeader(buf, cuckooMagic, c.hash)
+	buf = binary.AppendUvarint(buf, uint64(c.fp))
+	buf = binary.AppendUvarint(buf, c.mask+1)
+	buf = binary.AppendUvarint(buf, uint64(c.n))
+
+	for _, f := range c.slots {
+		buf = binary.LittleEndian.AppendUint16(buf, f)
+	}
+
+	return appendChecksum(buf), nil
+}
+
+// UnmarshalBinary заменяет содержимое фильтра фильтром, записанным MarshalBinary.
+// Фильтр должен использовать ту же хэш функцию, с которой был записан.
+// Если данные повреждены, то фильтр не изменяется.
+func (c *Cuckoo) UnmarshalBinary(data []byte) error {
+	body, err := readHeader(data, cuckooMagic, c.hash)
+	if err != nil {
+		return err
+	}
+
+	r := &reader{data: body}
+	fp, buckets, n := r.uvarint(), r.uvarint(), r.uvarint()
+
+	if r.err != nil {
+		return r.err
+	}
+
+	if fp < 4 || fp > 16 || buckets == 0 || buckets&(buckets-1) != 0 ||
+		uint64(len(r.data)) != buckets*bucketSize*2 || n > buckets*bucketSize {
+		return ErrFormat
+	}
+
+	slots := make([]uint16, buckets*bucketSize)
+	for i := range slots {
+		slots[i] = binary.LittleEndian.Uint16(r.data[i*2:])
+	}
+
+	c.slots, c.mask, c.fp, c.n = slots, buckets-1, int(fp), int(n)
+	c.rng = rand.New(rand.NewPCG(buckets, fp))
+
+	return nil
+}
@@ -0,0 +1,120 @@
+package filter
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/polRk/data_structures_and_algorithms/1.1/hashtable"
+)
+
+func TestCuckooFull(t *testing.T) {
+	for _, h := range []hashtable.Hasher[string]{hashtable.Pearson8, hashtable.FNV1a{}} {
+		t.Run(strconv.Itoa(h.Bits()), func(t *testing.T) {
+			c := NewCuckoo(h, 1000, 0.01)
+
+			var added []string
+
+			for i := 0; ; i++ {
+				if i > len(c.slots) {
+					t.Fatalf("добавлено %d строк без ErrFull при %d ячейках", i, len(c.slots))
+				}
+
+				key := "key-" + strconv.Itoa(i)
+				before, _ := c.MarshalBinary()
+
+				err := c.Add(key)
+				if err == nil {
+					added = append(added, key)
+					continue
+				}
+
+				if !errors.Is(err, ErrFull) {
+					t.Fatalf("Add(%q) = %v", key, err)
+				}
+
+				// Отмененные вытеснения возвращают отпечатки на место
+				if after, _ := c.MarshalBinary(); !bytes.Equal(before, after) {
+					t.Fatal("фильтр изменился после ErrFull")
+				}
+
+				break
+			}
+
+			if c.Len() != len(added) {
+				t.Errorf("Len() = %d, want %d", c.Len(), len(added))
+			}
+
+			// Вытеснения позволяют заполнить большую часть ячеек
+			if load := float64(c.Len()) / float64(len(c.slots)); load < 0.9 {
+				t.Errorf("заполнено %.2f ячеек до ErrFull, want не меньше 0.9", load)
+			}
+
+			for _, key := range added {
+				if !c.Contains(key) {
+					t.Fatalf("Contains(%q) = false после Add", key)
+				}
+			}
+		})
+	}
+}
+
+func TestCuckooDelete(t *testing.T) {
+	c := NewCuckoo(hashtable.FNV1a{}, 1000, 0.001)
+
+	if c.Delete("missing") {
+		t.Error("Delete(missing) = true в пустом фильтре")
+	}
+
+	for i := range 1000 {
+		if err := c.Add("key-" + strconv.Itoa(i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Повторное добавление хранит второй отпечаток
+	for range 2 {
+		if err := c.Add("dup"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for i := 0; i < 1000; i += 2 {
+		if key := "key-" + strconv.Itoa(i); !c.Delete(key) {
+			t.Fatalf("Delete(%q) = false", key)
+		}
+	}
+
+	if !c.Delete("dup") || !c.Contains("dup") {
+		t.Error("после удаления одного отпечатка dup не найдена")
+	}
+
+	if c.Len() != 501 {
+		t.Errorf("Len() = %d, want 501", c.Len())
+	}
+
+	for i := 1; i < 1000; i += 2 {
+		if key := "key-" + strconv.Itoa(i); !c.Contains(key) {
+			t.Fatalf("Contains(%q) = false после удаления других строк", key)
+		}
+	}
+}
+
+func TestCuckooAlt(t *testing.T) {
+	for _, expected := range []int{1, 100, 10000} {
+		c := NewCuckoo(hashtable.FNV1a{}, expected, 0.01)
+		buckets := c.mask + 1
+
+		for i := range buckets {
+			for f := uint16(1); f < 1<<c.fp; f += 7 {
+				j := c.alt(i, f)
+
+				if j >= buckets || c.alt(j, f) != i {
+					t.Fatalf("alt(%d, %d) = %d, alt(%d, %d) = %d, корзин %d",
+						i, f, j, j, f, c.alt(j, f), buckets)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,130 @@
+// Package filter реализует вероятностные фильтры принадлежности
+// для проверки перед обращением к хэш-таблице или диску:
+// фильтр Блума и кукушкин фильтр.
+//
+// Фильтр отвечает «возможно есть» или «точно нет»: строка, добавленная
+// в фильтр, всегда найдется, а отсутствующая строка найдется с вероятностью
+// ложного срабатывания, которая задается при создании фильтра.
+// Фильтры принимают любую хэш функцию строк hashtable.Hasher, в том числе
+// Pearson8: если хэш функция возвращает меньше 64 бит, то недостающие биты
+// получаются хэшированием строки с разными префиксами.
+package filter
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+
+	"github.com/polRk/data_structures_and_algorithms/1.1/hashtable"
+)
+
+// Ошибки фильтров.
+var (
+	ErrFull         = errors.New("filter: фильтр заполнен")
+	ErrFormat       = errors.New("filter: неверный формат фильтра")
+	ErrVersion      = errors.New("filter: неподдерживаемая версия фильтра")
+	ErrChecksum     = errors.New("filter: неверная контрольная сумма фильтра")
+	ErrHashMismatch = errors.New("filter: хэш функция не совпадает с хэш функцией фильтра")
+)
+
+// formatVersion задает версию двоичного вида фильтров.
+const formatVersion = 1
+
+var castagnoli = crc32.MakeTable(crc32.Castagnoli)
+
+// hash64 возвращает 64-битное хэш значение строки.
+// Если хэш функция возвращает меньше 64 бит, то следующие биты
+// берутся из хэш значений строки с префиксами 1, 2 и т. д.
+func hash64(h hashtable.Hasher[string], key string) uint64 {
+	width := h.Bits()
+	v := h.Hash(key)
+
+	if width >= 64 || width <= 0 {
+		return v
+	}
+
+	v &= 1<<width - 1
+
+	for shift, prefix := width, byte(1); shift < 64; shift, prefix = shift+width, prefix+1 {
+		v |= h.Hash(string(prefix)+key) << shift
+	}
+
+	return v
+}
+
+// split возвращает два независимых хэш значения строки
+// для двойного хэширования.
+func split(h hashtable.Hasher[string], key string) (uint64, uint64) {
+	v := mix(hash64(h, key))
+	return v, mix(v ^ 0x9E3779B97F4A7C15)
+}
+
+// mix перемешивает биты хэш значения (финализатор MurmurHash3).
+func mix(h uint64) uint64 {
+	h ^= h >> 33
+	h *= 0xFF51AFD7ED558CCD
+	h ^= h >> 33
+	h *= 0xC4CEB9FE1A85EC53
+	h ^= h >> 33
+
+	return h
+}
+
+// appendHeader добавляет к buf заголовок двоичного вида фильтра.
+func appendHeader(buf []byte, magic string, h hashtable.Hasher[string]) []byte {
+	buf = append(buf, magic...)
+	return append(buf, formatVersion, byte(h.Bits()))
+}
+
+// appendChecksum добавляет к buf контрольную сумму всех его байт.
+func appendChecksum(buf []byte) []byte {
+	return binary.BigEndian.AppendUint32(buf, crc32.Checksum(buf, castagnoli))
+}
+
+// readHeader проверяет заголовок и контрольную сумму двоичного вида фильтра.
+// Возвращает байты после заголовка без контрольной суммы.
+func readHeader(data []byte, magic string, h hashtable.Hasher[string]) ([]byte, error) {
+	header := len(magic) + 2
+	if len(data) < header+4 || string(data[:len(magic)]) != magic {
+		return nil, ErrFormat
+	}
+
+	body := data[:len(data)-4]
+	if crc32.Checksum(body, castagnoli) != binary.BigEndian.Uint32(data[len(body):]) {
+		return nil, ErrChecksum
+	}
+
+	if body[len(magic)] != formatVersion {
+		return nil, ErrVersion
+	}
+
+	// Хэш функция не сохраняется, проверяю хотя бы ее разрядность
+	if int(body[len(magic)+1]) != h.Bits() {
+		return nil, ErrHashMismatch
+	}
+
+	return body[header:], nil
+}
+
+// reader читает числа двоичного вида фильтра.
+type reader struct {
+	data []byte
+	err  error
+}
+
+// uvarint читает число в формате uvarint.
+func (r *reader) uvarint() uint64 {
+	if r.err != nil {
+		return 0
+	}
+
+	v, n := binary.Uvarint(r.data)
+	if n <= 0 {
+		r.err = ErrFormat
+		return 0
+	}
+
+	r.data = r.data[n:]
+
+	return v
+}
@@ -0,0 +1,115 @@
+package filter
+
+import (
+	"bytes"
+	"encoding"
+	"errors"
+	"testing"
+
+	"github.com/polRk/data_structures_and_algorithms/1.1/hashtable"
+)
+
+// binaryFilter представляет фильтр, записываемый в двоичном виде.
+type binaryFilter interface {
+	Contains(key string) bool
+	Len() int
+	encoding.BinaryMarshaler
+	encoding.BinaryUnmarshaler
+}
+
+func TestMarshalBinary(t *testing.T) {
+	keys := []string{"a", "b", "c", "hello", "world"}
+
+	tests := []struct {
+		name string
+		new  func(h hashtable.Hasher[string], expected int) binaryFilter
+		add  func(f binaryFilter, key string)
+	}{
+		{
+			name: "bloom",
+			new: func(h hashtable.Hasher[string], expected int) binaryFilter {
+				return NewBloom(h, expected, 0.01)
+			},
+			add: func(f binaryFilter, key string) { f.(*Bloom).Add(key) },
+		},
+		{
+			name: "cuckoo",
+			new: func(h hashtable.Hasher[string], expected int) binaryFilter {
+				return NewCuckoo(h, expected, 0.01)
+			},
+			add: func(f binaryFilter, key string) {
+				if err := f.(*Cuckoo).Add(key); err != nil {
+					t.Fatal(err)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := tt.new(hashtable.FNV1a{}, 100)
+			for _, key := range keys {
+				tt.add(f, key)
+			}
+
+			data, err := f.MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// Размер восстановленного фильтра берется из данных
+			g := tt.new(hashtable.FNV1a{}, 1)
+			if err := g.UnmarshalBinary(data); err != nil {
+				t.Fatalf("UnmarshalBinary: %v", err)
+			}
+
+			if again, _ := g.MarshalBinary(); !bytes.Equal(again, data) || g.Len() != len(keys) {
+				t.Fatalf("восстановленный фильтр отличается, Len() = %d", g.Len())
+			}
+
+			for _, key := range keys {
+				if !g.Contains(key) {
+					t.Fatalf("Contains(%q) = false после восстановления", key)
+				}
+			}
+
+			body := data[:len(data)-4]
+
+			version := bytes.Clone(body)
+			version[4] = formatVersion + 1
+
+			flipped := bytes.Clone(data)
+			flipped[len(body)-1] ^= 1
+
+			corrupt := []struct {
+				name string
+				hash hashtable.Hasher[string]
+				data []byte
+				err  error
+			}{
+				{"другая разрядность хэш функции", hashtable.Pearson8, data, ErrHashMismatch},
+				{"короткие данные", hashtable.FNV1a{}, data[:5], ErrFormat},
+				{"контрольная сумма", hashtable.FNV1a{}, flipped, ErrChecksum},
+				{"версия", hashtable.FNV1a{}, appendChecksum(version), ErrVersion},
+				{"обрезаны данные", hashtable.FNV1a{}, appendChecksum(bytes.Clone(body[:len(body)-1])), ErrFormat},
+			}
+
+			for _, c := range corrupt {
+				t.Run(c.name, func(t *testing.T) {
+					g := tt.new(c.hash, 10)
+					tt.add(g, "x")
+					before, _ := g.MarshalBinary()
+
+					if err := g.UnmarshalBinary(c.data); !errors.Is(err, c.err) {
+						t.Fatalf("UnmarshalBinary() = %v, want %v", err, c.err)
+					}
+
+					// При ошибке фильтр не изменяется
+					if after, _ := g.MarshalBinary(); !bytes.Equal(before, after) {
+						t.Error("фильтр изменился после ошибки")
+					}
+				})
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package hashtable
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock представляет источник времени для срока жизни элементов.
+type Clock interface {
+	// Now возвращает текущее время.
+	Now() time.Time
+	// After возвращает канал, в который будет отправлено время,
+	// когда пройдет d.
+	After(d time.Duration) <-chan time.Time
+}
+
+// systemClock представляет системные часы.
+type systemClock struct{}
+
+func (systemClock) Now() time.Time                         { return time.Now() }
+func (systemClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// ManualClock представляет часы, время которых меняется только вызовами
+// Set и Advance. Используется, чтобы проверять истечение срока жизни
+// элементов и работу очистки без ожидания.
+// Методы можно вызывать из нескольких горутин.
+type ManualClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []manualWaiter
+}
+
+// manualWaiter представляет ожидание, созданное ManualClock.After.
+type manualWaiter struct {
+	at time.Time
+	ch chan time.Time
+}
+
+// NewManualClock возвращает часы, показывающие время now.
+func NewManualClock(now time.Time) *ManualClock {
+	return &ManualClock{now: now}
+}
+
+// Now возвращает текущее время часов.
+func (c *ManualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+// After возвращает канал, в который будет отправлено время,
+// когда часы будут переведены не меньше чем на d вперед.
+func (c *ManualClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	w := manualWaiter{at: c.now.Add(d), ch: make(chan time.Time, 1)}
+	if d <= 0 {
+		w.ch <- c.now
+		return w.ch
+	}
+
+	c.waiters = append(c.waiters, w)
+
+	return w.ch
+}
+
+// Advance переводит часы на d вперед.
+func (c *ManualClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	now := c.now.Add(d)
+	c.mu.Unlock()
+
+	c.Set(now)
+}
+
+// Set устанавливает время часов и срабатывает ожидания,
+// время которых наступило.
+func (c *ManualClock) Set(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = now
+
+	// Оставляю только ожидания, время которых еще не наступило
+	waiters := c.waiters[:0]
+	for _, w := range c.waiters {
+		if w.at.After(now) {
+			waiters = append(waiters, w)
+		} else {
+			w.ch <- now
+		}
+	}
+
+	clear(c.waiters[len(waiters):])
+	c.waiters = waiters
+}
+
+// Waiters возвращает количество ожиданий, время которых еще не наступило.
+// Позволяет дождаться, пока фоновая очистка начнет ожидание.
+func (c *ManualClock) Waiters() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.waiters)
+}
@@ -0,0 +1,275 @@
+package hashtable
+
+import (
+	"iter"
+	"sync"
+	"time"
+)
+
+// ConcurrentTable представляет хэш-таблицу,
+// безопасную для использования из нескольких горутин.
+// Таблица делится на сегменты по младшим битам хэш значения,
+// как и Table, и каждый сегмент защищен своей блокировкой,
+// поэтому операции с разными сегментами не мешают друг другу.
+type ConcurrentTable[K comparable, V any] struct {
+	shards []shard[K, V]
+	mask   uint64
+	hash   Hasher[K]
+	norm   func(K) K // Нормализация ключа или nil
+	clock  Clock     // Часы для срока жизни элементов
+}
+
+// shard представляет сегмент ConcurrentTable.
+type shard[K comparable, V any] struct {
+	mu    sync.RWMutex
+	table *Table[K, V]
+}
+
+// shiftHasher представляет хэш функцию,
+// отбрасывающую младшие биты, по которым выбран сегмент.
+type shiftHasher[K comparable] struct {
+	hash  Hasher[K]
+	shift int
+}
+
+// Hash возвращает хэш значение ключа без младших бит.
+func (h shiftHasher[K]) Hash(key K) uint64 {
+	return h.hash.Hash(key) >> h.shift
+}
+
+// Bits возвращает разрядность хэш значения.
+func (h shiftHasher[K]) Bits() int {
+	return h.hash.Bits() - h.shift
+}
+
+// NewConcurrentTable возвращает пустую таблицу,
+// использующую переданную хэш функцию.
+// Настройки, кроме WithShardBits, применяются к таблице каждого сегмента.
+func NewConcurrentTable[K comparable, V any](hash Hasher[K], opts ...Option[K, V]) *ConcurrentTable[K, V] {
+	o := newOptions(hash.Bits(), opts)
+
+	t := &ConcurrentTable[K, V]{
+		shards: make([]shard[K, V], 1<<o.shardBits),
+		mask:   1<<o.shardBits - 1,
+		hash:   hash,
+		norm:   o.normalize,
+		clock:  o.clock,
+	}
+
+	// Таблицы сегментов используют оставшиеся биты хэш значения.
+	// Ключ нормализуется до выбора сегмента, поэтому
+	// таблицам сегментов нормализация не нужна.
+	// Порядок обхода меняется при чтении, поэтому сегменты
+	// не упорядочиваются, чтобы чтение не требовало
+	// исключительной блокировки
+	h := shiftHasher[K]{hash: hash, shift: o.shardBits}
+	opts = append(opts[:len(opts):len(opts)], WithNormalizer[K, V](), unordered[K, V])
+
+	for i := range t.shards {
+		t.shards[i].table = NewTable[K, V](h, opts...)
+	}
+
+	return t
+}
+
+// unordered отключает порядок обхода и ограничение количества элементов.
+func unordered[K comparable, V any](o *options[K, V]) {
+	o.order = Unordered
+	o.capacity = 0
+	o.evict = nil
+}
+
+// shard возвращает сегмент, которому принадлежит ключ.
+func (t *ConcurrentTable[K, V]) shard(key K) *shard[K, V] {
+	return &t.shards[t.hash.Hash(key)&t.mask]
+}
+
+// normalize возвращает нормальную форму ключа,
+// если таблице задана нормализация.
+func (t *ConcurrentTable[K, V]) normalize(key K) K {
+	if t.norm == nil {
+		return key
+	}
+
+	return t.norm(key)
+}
+
+// Len возвращает количество элементов в таблице.
+// Если таблица изменяется, то результат приблизителен.
+func (t *ConcurrentTable[K, V]) Len() int {
+	n := 0
+
+	for i := range t.shards {
+		s := &t.shards[i]
+
+		s.mu.RLock()
+		n += s.table.Len()
+		s.mu.RUnlock()
+	}
+
+	return n
+}
+
+// Put записывает значение по ключу.
+// Если элемента с таким ключом нет, то добавляет его.
+func (t *ConcurrentTable[K, V]) Put(key K, value V) {
+	key = t.normalize(key)
+	s := t.shard(key)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.table.Put(key, value)
+}
+
+// Get возвращает значение по ключу и true,
+// если элемент существует.
+func (t *ConcurrentTable[K, V]) Get(key K) (V, bool) {
+	key = t.normalize(key)
+	s := t.shard(key)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	// Peek не изменяет таблицу сегмента, поэтому
+	// истекший элемент остается до изменения сегмента или очистки
+	return s.table.Peek(key)
+}
+
+// GetOrInsert возвращает значение существующего элемента и true.
+// Если элемента нет, то добавляет переданное значение
+// и возвращает его и false.
+func (t *ConcurrentTable[K, V]) GetOrInsert(key K, value V) (V, bool) {
+	key = t.normalize(key)
+	s := t.shard(key)
+
+	// Сначала пробую найти элемент под блокировкой на чтение
+	s.mu.RLock()
+	v, ok := s.table.Peek(key)
+	s.mu.RUnlock()
+
+	if ok {
+		return v, true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.table.GetOrInsert(key, value)
+}
+
+// PutTTL записывает значение по ключу со сроком жизни ttl.
+// Если ttl не положителен, то элемент не истекает.
+// Если элемента с таким ключом нет, то добавляет его.
+func (t *ConcurrentTable[K, V]) PutTTL(key K, value V, ttl time.Duration) {
+	key = t.normalize(key)
+	s := t.shard(key)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.table.PutTTL(key, value, ttl)
+}
+
+// Update заменяет значение по ключу результатом fn.
+// Если элемента нет, то fn получает нулевое значение,
+// а результат добавляется в таблицу.
+// fn вызывается под блокировкой сегмента и не должна обращаться к таблице.
+// Возвращает новое значение.
+func (t *ConcurrentTable[K, V]) Update(key K, fn func(old V) V) V {
+	key = t.normalize(key)
+	s := t.shard(key)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.table.Update(key, fn)
+}
+
+// Delete удаляет элемент из таблицы.
+// Возвращает значение удаленного элемента и true,
+// если элемент существовал.
+func (t *ConcurrentTable[K, V]) Delete(key K) (V, bool) {
+	key = t.normalize(key)
+	s := t.shard(key)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.table.Delete(key)
+}
+
+// Sweep удаляет из таблицы все истекшие элементы,
+// блокируя сегменты по очереди.
+// Функция WithExpire вызывается под блокировкой сегмента
+// и не должна обращаться к таблице.
+// Возвращает количество удаленных элементов.
+func (t *ConcurrentTable[K, V]) Sweep() int {
+	n := 0
+
+	for i := range t.shards {
+		s := &t.shards[i]
+
+		s.mu.Lock()
+		n += s.table.Sweep()
+		s.mu.Unlock()
+	}
+
+	return n
+}
+
+// StartSweeper запускает в отдельной горутине очистку Sweep
+// каждые interval по часам таблицы.
+// Возвращает функцию, которая останавливает очистку
+// и дожидается завершения горутины.
+func (t *ConcurrentTable[K, V]) StartSweeper(interval time.Duration) (stop func()) {
+	return startSweeper(t.clock, interval, func() { t.Sweep() })
+}
+
+// All возвращает итератор по парам ключ-значение таблицы.
+// Элементы каждого сегмента копируются под блокировкой,
+// а выдаются без нее, поэтому тело цикла может изменять таблицу.
+// Итерация видит каждый сегмент в состоянии на момент его копирования.
+func (t *ConcurrentTable[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		var entries []entry[K, V]
+
+		for i := range t.shards {
+			entries = t.shards[i].snapshot(entries[:0])
+
+			for _, e := range entries {
+				if !yield(e.key, e.value) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// entry представляет копию пары ключ-значение.
+type entry[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+// snapshot добавляет к entries копии элементов сегмента.
+func (s *shard[K, V]) snapshot(entries []entry[K, V]) []entry[K, V] {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	// Обхожу списки напрямую: итераторы Table
+	// изменяют счетчик итераций и требуют блокировки на запись
+	now := s.table.opts.clock.Now().UnixNano()
+
+	for seg := s.table.head; seg != nil; seg = seg.next {
+		for e := seg.list; e != nil; e = e.next {
+			if e.expiredAt(now) {
+				continue
+			}
+
+			entries = append(entries, entry[K, V]{key: e.key, value: e.value})
+		}
+	}
+
+	return entries
+}
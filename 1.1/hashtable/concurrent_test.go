@@ -0,0 +1,229 @@
+package hashtable
+
+import (
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// Стресс-тесты ConcurrentTable имеют смысл с детектором гонок:
+//
+//	go test -race -run Concurrent ./hashtable
+
+func TestConcurrentTableStress(t *testing.T) {
+	clock := NewManualClock(time.Unix(0, 0))
+
+	var expired atomic.Int64
+
+	tbl := NewConcurrentTable[string, int](FNV1a{},
+		WithShardBits[string, int](4),
+		WithBits[string, int](1),
+		WithTTL[string, int](time.Minute),
+		WithClock[string, int](clock),
+		WithExpire(func(string, int) { expired.Add(1) }),
+	)
+
+	stop := tbl.StartSweeper(30 * time.Second)
+
+	const (
+		workers = 8
+		ops     = 5000
+		keys    = 512
+	)
+
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+
+	// Часы идут, пока работают горутины, и будят фоновую очистку
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				clock.Advance(10 * time.Second)
+				time.Sleep(50 * time.Microsecond)
+			}
+		}
+	}()
+
+	for w := range workers {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := range ops {
+				key := strconv.Itoa((i*31 + w*17) % keys)
+
+				switch i % 8 {
+				case 0, 1:
+					tbl.Put(key, i)
+				case 2:
+					tbl.Get(key)
+				case 3:
+					tbl.Update(key, func(old int) int { return old + 1 })
+				case 4:
+					tbl.Delete(key)
+				case 5:
+					tbl.GetOrInsert(key, i)
+				case 6:
+					tbl.PutTTL(key, i, time.Duration(i%3)*time.Second)
+				case 7:
+					// Тело цикла изменяет таблицу, что разрешено для All
+					n := 0
+					for k := range tbl.All() {
+						if n++; n%16 == 0 {
+							tbl.Delete(k)
+						}
+
+						if n > 64 {
+							break
+						}
+					}
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(done)
+	stop()
+
+	tbl.Sweep()
+
+	n := 0
+	for range tbl.All() {
+		n++
+	}
+
+	if tbl.Len() != n {
+		t.Errorf("Len() = %d, All() выдал %d элементов", tbl.Len(), n)
+	}
+
+	if expired.Load() == 0 {
+		t.Error("ни один элемент не истек")
+	}
+}
+
+func TestConcurrentTableUpdate(t *testing.T) {
+	tbl := NewConcurrentTable[string, int](FNV1a{}, WithShardBits[string, int](2))
+
+	const (
+		workers = 8
+		updates = 2048
+		keys    = 64
+	)
+
+	var wg sync.WaitGroup
+
+	for range workers {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := range updates {
+				tbl.Update(strconv.Itoa(i%keys), func(old int) int { return old + 1 })
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	// Update выполняется под блокировкой, поэтому ни одно увеличение не теряется
+	for i := range keys {
+		if got, _ := tbl.Get(strconv.Itoa(i)); got != workers*updates/keys {
+			t.Errorf("Get(%d) = %d, want %d", i, got, workers*updates/keys)
+		}
+	}
+}
+
+// store представляет таблицу для сравнения параллельного доступа.
+type store interface {
+	Load(key string) bool
+	Store(key string)
+}
+
+// concurrentStore представляет ConcurrentTable как store.
+type concurrentStore struct {
+	t *ConcurrentTable[string, struct{}]
+}
+
+func (s concurrentStore) Load(key string) bool {
+	_, ok := s.t.Get(key)
+	return ok
+}
+
+func (s concurrentStore) Store(key string) {
+	s.t.Put(key, struct{}{})
+}
+
+// syncStore представляет sync.Map как store.
+type syncStore struct {
+	m *sync.Map
+}
+
+func (s syncStore) Load(key string) bool {
+	_, ok := s.m.Load(key)
+	return ok
+}
+
+func (s syncStore) Store(key string) {
+	s.m.Store(key, struct{}{})
+}
+
+// benchmarkParallel заполняет таблицу строками и измеряет параллельный доступ:
+// каждая десятая операция записывает строку, остальные читают.
+func benchmarkParallel(b *testing.B, newStore func() store) {
+	for _, n := range []int{1000, 100000} {
+		b.Run(strconv.Itoa(n), func(b *testing.B) {
+			s := newStore()
+			keys := make([]string, n)
+
+			for i := range keys {
+				keys[i] = "key-" + strconv.Itoa(i)
+				s.Store(keys[i])
+			}
+
+			var seq, missing atomic.Int64
+
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				// Каждая горутина начинает со своего места в списке строк
+				i := int(seq.Add(1)) * 7919
+
+				for pb.Next() {
+					key := keys[i%len(keys)]
+
+					if i%10 == 0 {
+						s.Store(key)
+					} else if !s.Load(key) {
+						missing.Add(1)
+					}
+
+					i++
+				}
+			})
+
+			// FailNow нельзя вызывать из горутин RunParallel
+			if missing.Load() > 0 {
+				b.Fatalf("не найдено %d элементов", missing.Load())
+			}
+		})
+	}
+}
+
+func BenchmarkParallelConcurrentTable(b *testing.B) {
+	benchmarkParallel(b, func() store {
+		return concurrentStore{NewConcurrentTable[string, struct{}](FNV1a{})}
+	})
+}
+
+func BenchmarkParallelSyncMap(b *testing.B) {
+	benchmarkParallel(b, func() store {
+		return syncStore{&sync.Map{}}
+	})
+}
@@ -0,0 +1,173 @@
+package hashtable
+
+import (
+	"container/heap"
+	"iter"
+	"slices"
+)
+
+// Counter представляет мультимножество: таблицу,
+// в которой повторное добавление ключа увеличивает его счетчик.
+type Counter[K comparable] struct {
+	table *Table[K, int]
+	total int // Сумма всех счетчиков
+}
+
+// KeyCount представляет ключ и его счетчик.
+type KeyCount[K comparable] struct {
+	Key   K   `json:"key"`
+	Count int `json:"count"`
+}
+
+// NewCounter возвращает пустое мультимножество,
+// использующее переданную хэш функцию.
+// Ключи, удаленные из-за WithCapacity или истекшие по WithTTL,
+// вычитаются из суммы счетчиков.
+func NewCounter[K comparable](hash Hasher[K], opts ...Option[K, int]) *Counter[K] {
+	c := &Counter[K]{}
+
+	opts = append(opts[:len(opts):len(opts)], c.track)
+	c.table = NewTable[K, int](hash, opts...)
+
+	return c
+}
+
+// track оборачивает функции удаления при переполнении и истечении,
+// чтобы счетчики ключей, которые таблица удаляет сама, вычитались из суммы.
+func (c *Counter[K]) track(o *options[K, int]) {
+	evict, expire := o.evict, o.expire
+
+	o.evict = func(key K, n int) {
+		c.total -= n
+
+		if evict != nil {
+			evict(key, n)
+		}
+	}
+
+	o.expire = func(key K, n int) {
+		c.total -= n
+
+		if expire != nil {
+			expire(key, n)
+		}
+	}
+}
+
+// Len возвращает количество различных ключей.
+func (c *Counter[K]) Len() int {
+	return c.table.Len()
+}
+
+// Total возвращает сумму счетчиков всех ключей.
+func (c *Counter[K]) Total() int {
+	return c.total
+}
+
+// Add добавляет ключ один раз.
+// Возвращает новое значение счетчика.
+func (c *Counter[K]) Add(key K) int {
+	return c.AddN(key, 1)
+}
+
+// AddN добавляет ключ n раз.
+// Если n не положительно, то мультимножество не изменяется.
+// Возвращает новое значение счетчика.
+func (c *Counter[K]) AddN(key K, n int) int {
+	if n <= 0 {
+		return c.Count(key)
+	}
+
+	c.total += n
+
+	return c.table.Update(key, func(old int) int {
+		return old + n
+	})
+}
+
+// Count возвращает счетчик ключа или 0, если ключа нет.
+func (c *Counter[K]) Count(key K) int {
+	n, _ := c.table.Get(key)
+	return n
+}
+
+// Remove уменьшает счетчик ключа на n.
+// Если счетчик становится не больше нуля, то ключ удаляется.
+// Срок жизни оставшегося ключа не изменяется.
+// Возвращает новое значение счетчика.
+func (c *Counter[K]) Remove(key K, n int) int {
+	e := c.table.Find(key)
+	if e == nil {
+		return 0
+	}
+
+	if n <= 0 {
+		return e.value
+	}
+
+	if n >= e.value {
+		c.total -= e.value
+		c.table.Delete(key)
+
+		return 0
+	}
+
+	// Меняю значение в элементе, чтобы Put не продлил срок жизни
+	e.value -= n
+	c.total -= n
+
+	return e.value
+}
+
+// All возвращает итератор по ключам и их счетчикам.
+// Изменение мультимножества во время итерации описано в Table.Elements.
+func (c *Counter[K]) All() iter.Seq2[K, int] {
+	return c.table.All()
+}
+
+// TopK возвращает не более k ключей с наибольшими счетчиками
+// в порядке убывания счетчиков. Порядок ключей
+// с одинаковыми счетчиками не определен.
+func (c *Counter[K]) TopK(k int) []KeyCount[K] {
+	if k <= 0 {
+		return nil
+	}
+
+	// Храню k наибольших счетчиков в куче с наименьшим в вершине
+	h := make(countHeap[K], 0, min(k, c.Len()))
+
+	for key, n := range c.table.All() {
+		if len(h) < k {
+			heap.Push(&h, KeyCount[K]{Key: key, Count: n})
+		} else if n > h[0].Count {
+			h[0] = KeyCount[K]{Key: key, Count: n}
+			heap.Fix(&h, 0)
+		}
+	}
+
+	top := []KeyCount[K](h)
+	slices.SortFunc(top, func(a, b KeyCount[K]) int {
+		return b.Count - a.Count
+	})
+
+	return top
+}
+
+// countHeap представляет кучу счетчиков с наименьшим в вершине.
+type countHeap[K comparable] []KeyCount[K]
+
+func (h countHeap[K]) Len() int           { return len(h) }
+func (h countHeap[K]) Less(i, j int) bool { return h[i].Count < h[j].Count }
+func (h countHeap[K]) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *countHeap[K]) Push(x any) {
+	*h = append(*h, x.(KeyCount[K]))
+}
+
+func (h *countHeap[K]) Pop() any {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+
+	return x
+}
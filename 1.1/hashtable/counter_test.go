@@ -0,0 +1,174 @@
+package hashtable
+
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+// checkTotal сравнивает Total с суммой счетчиков ключей.
+func checkTotal(t *testing.T, c *Counter[string]) {
+	t.Helper()
+
+	sum := 0
+	for _, n := range c.All() {
+		sum += n
+	}
+
+	if c.Total() != sum {
+		t.Fatalf("Total() = %d, сумма счетчиков %d", c.Total(), sum)
+	}
+}
+
+func TestCounterCapacity(t *testing.T) {
+	var evicted []string
+
+	c := NewCounter(FNV1a{},
+		WithCapacity[string, int](1),
+		WithEvict(func(key string, n int) { evicted = append(evicted, key) }),
+	)
+
+	c.Add("a")
+	c.AddN("b", 3)
+
+	if c.Len() != 1 || c.Total() != 3 {
+		t.Errorf("Len() = %d, Total() = %d, want 1, 3", c.Len(), c.Total())
+	}
+
+	// Функция удаления пользователя вызывается и после подсчета
+	if len(evicted) != 1 || evicted[0] != "a" {
+		t.Errorf("вытеснены %v, want [a]", evicted)
+	}
+
+	checkTotal(t, c)
+}
+
+func TestCounterTTL(t *testing.T) {
+	clock := NewManualClock(time.Unix(0, 0))
+	expired := 0
+
+	c := NewCounter(FNV1a{},
+		WithTTL[string, int](time.Minute),
+		WithClock[string, int](clock),
+		WithExpire(func(string, int) { expired++ }),
+	)
+
+	c.AddN("a", 2)
+	c.AddN("b", 5)
+	clock.Advance(30 * time.Second)
+	c.Add("c")
+
+	// "a" истекает при обращении, "b" при очистке
+	clock.Advance(45 * time.Second)
+
+	if n := c.Add("a"); n != 1 {
+		t.Errorf("Add(a) после истечения = %d, want 1", n)
+	}
+
+	c.table.Sweep()
+
+	if c.Len() != 2 || c.Total() != 2 || expired != 2 {
+		t.Errorf("Len() = %d, Total() = %d, истекло %d, want 2, 2, 2", c.Len(), c.Total(), expired)
+	}
+
+	checkTotal(t, c)
+}
+
+func TestCounterRemove(t *testing.T) {
+	tests := []struct {
+		name   string
+		key    string
+		n      int
+		want   int // Новое значение счетчика
+		length int
+		total  int
+	}{
+		{"часть", "a", 2, 3, 2, 5},
+		{"до нуля", "a", 5, 0, 1, 2},
+		{"больше счетчика", "a", 7, 0, 1, 2},
+		{"ноль", "a", 0, 5, 2, 7},
+		{"отрицательное", "a", -3, 5, 2, 7},
+		{"нет ключа", "x", 1, 0, 2, 7},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewCounter[string](FNV1a{})
+			c.AddN("a", 5)
+			c.AddN("b", 2)
+
+			if got := c.Remove(tt.key, tt.n); got != tt.want {
+				t.Errorf("Remove(%q, %d) = %d, want %d", tt.key, tt.n, got, tt.want)
+			}
+
+			if c.Count(tt.key) != tt.want || c.Len() != tt.length || c.Total() != tt.total {
+				t.Errorf("Count() = %d, Len() = %d, Total() = %d, want %d, %d, %d",
+					c.Count(tt.key), c.Len(), c.Total(), tt.want, tt.length, tt.total)
+			}
+
+			checkTotal(t, c)
+		})
+	}
+}
+
+func TestCounterRemoveKeepsTTL(t *testing.T) {
+	clock := NewManualClock(time.Unix(0, 0))
+
+	c := NewCounter(FNV1a{},
+		WithTTL[string, int](time.Minute),
+		WithClock[string, int](clock),
+	)
+
+	c.AddN("a", 3)
+	expires := c.table.Find("a").Expires()
+
+	clock.Advance(30 * time.Second)
+	c.Remove("a", 1)
+
+	if got := c.table.Find("a").Expires(); !got.Equal(expires) {
+		t.Errorf("Expires() после Remove = %v, want %v", got, expires)
+	}
+
+	clock.Advance(30 * time.Second)
+
+	if c.Count("a") != 0 || c.Total() != 0 {
+		t.Errorf("Count() = %d, Total() = %d после истечения, want 0, 0", c.Count("a"), c.Total())
+	}
+}
+
+func TestCounterTopK(t *testing.T) {
+	tests := []struct {
+		k    int
+		want []int // Счетчики результата
+	}{
+		{-1, nil},
+		{0, nil},
+		{1, []int{9}},
+		{3, []int{9, 7, 4}},
+		{5, []int{9, 7, 4, 2, 1}},
+		{10, []int{9, 7, 4, 2, 1}},
+	}
+
+	c := NewCounter[string](FNV1a{})
+	counts := map[string]int{"a": 4, "b": 1, "c": 9, "d": 2, "e": 7}
+
+	for key, n := range counts {
+		c.AddN(key, n)
+	}
+
+	for _, tt := range tests {
+		t.Run(strconv.Itoa(tt.k), func(t *testing.T) {
+			top := c.TopK(tt.k)
+
+			if len(top) != len(tt.want) {
+				t.Fatalf("TopK(%d) = %v, want %d ключей", tt.k, top, len(tt.want))
+			}
+
+			for i, kc := range top {
+				if kc.Count != tt.want[i] || counts[kc.Key] != kc.Count {
+					t.Errorf("TopK(%d)[%d] = %v, want счетчик %d", tt.k, i, kc, tt.want[i])
+				}
+			}
+		})
+	}
+}
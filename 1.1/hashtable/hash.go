@@ -0,0 +1,127 @@
+package hashtable
+
+import "hash/maphash"
+
+// Hasher представляет хэш функцию ключей таблицы.
+type Hasher[K comparable] interface {
+	// Hash возвращает хэш значение ключа.
+	Hash(key K) uint64
+
+	// Bits возвращает разрядность хэш значения.
+	Bits() int
+}
+
+// pearsonTable содержит таблицу подстановки
+// хэш функции Пирсона.
+var pearsonTable = []uint8{
+	98, 6, 85, 150, 36, 23, 112, 164, 135, 207, 169, 5, 26, 64, 165, 219,
+	11, 61, 20, 68, 89, 130, 63, 52, 102, 24, 229, 132, 245, 80, 216, 195, 115,
+	12, 90, 168, 156, 203, 177, 120, 2, 190, 188, 7, 100, 185, 174, 243, 162, 10,
+	13, 237, 18, 253, 225, 8, 208, 172, 244, 255, 126, 101, 79, 145, 235, 228, 121,
+	14, 123, 251, 67, 250, 161, 0, 107, 97, 241, 111, 181, 82, 249, 33, 69, 55,
+	15, 59, 153, 29, 9, 213, 167, 84, 93, 30, 46, 94, 75, 151, 114, 73, 222,
+	16, 197, 96, 210, 45, 16, 227, 248, 202, 51, 152, 252, 125, 81, 206, 215, 186,
+	17, 39, 158, 178, 187, 131, 136, 1, 49, 50, 17, 141, 91, 47, 129, 60, 99,
+	18, 154, 35, 86, 171, 105, 34, 38, 200, 147, 58, 77, 118, 173, 246, 76, 254,
+	19, 133, 232, 196, 144, 198, 124, 53, 4, 108, 74, 223, 234, 134, 230, 157, 139,
+	20, 189, 205, 199, 128, 176, 19, 211, 236, 127, 192, 231, 70, 233, 88, 146, 44,
+	21, 183, 201, 22, 83, 13, 214, 116, 109, 159, 32, 95, 226, 140, 220, 57, 12,
+	22, 221, 31, 209, 182, 143, 92, 149, 184, 148, 62, 113, 65, 37, 27, 106, 166,
+	23, 3, 14, 204, 72, 21, 41, 56, 66, 28, 193, 40, 217, 25, 54, 179, 117,
+	24, 238, 87, 240, 155, 180, 170, 242, 212, 191, 163, 78, 218, 137, 194, 175, 110,
+	25, 43, 119, 224, 71, 122, 142, 42, 160, 104, 48, 247, 103, 15, 11, 138, 239,
+}
+
+// Pearson8Hash возвращает хэш значение переданной строки.
+func Pearson8Hash(str string) uint8 {
+	hash := uint8(len(str) % 256)
+
+	for i := 0; i < len(str); i++ {
+		hash = pearsonTable[hash^str[i]]
+	}
+
+	return hash
+}
+
+// Pearson представляет хэш функцию Пирсона,
+// значение которой состоит из указанного количества байт.
+type Pearson int
+
+// Разрядности хэш функции Пирсона.
+const (
+	Pearson8  Pearson = 1
+	Pearson16 Pearson = 2
+	Pearson32 Pearson = 4
+	Pearson64 Pearson = 8
+)
+
+// Hash возвращает хэш значение строки.
+// Каждый байт значения вычисляется отдельным проходом по строке
+// с измененным начальным состоянием, первый байт совпадает с Pearson8Hash.
+func (p Pearson) Hash(str string) uint64 {
+	var hash uint64
+
+	for j := 0; j < int(p); j++ {
+		h := uint8((len(str) + j) % 256)
+
+		for i := 0; i < len(str); i++ {
+			h = pearsonTable[h^str[i]]
+		}
+
+		hash |= uint64(h) << (8 * j)
+	}
+
+	return hash
+}
+
+// Bits возвращает разрядность хэш значения.
+func (p Pearson) Bits() int {
+	return 8 * int(p)
+}
+
+// FNV1a представляет 64-битную хэш функцию FNV-1a.
+type FNV1a struct{}
+
+// Hash возвращает хэш значение строки.
+func (FNV1a) Hash(str string) uint64 {
+	const (
+		offset = 14695981039346656037
+		prime  = 1099511628211
+	)
+
+	hash := uint64(offset)
+
+	for i := 0; i < len(str); i++ {
+		hash ^= uint64(str[i])
+		hash *= prime
+	}
+
+	return hash
+}
+
+// Bits возвращает разрядность хэш значения.
+func (FNV1a) Bits() int {
+	return 64
+}
+
+// Seeded представляет хэш функцию со случайным зерном.
+// Значения хэш функции невозможно подобрать заранее,
+// поэтому она устойчива к специально подобранным ключам.
+type Seeded[K comparable] struct {
+	seed maphash.Seed
+}
+
+// NewSeeded возвращает хэш функцию со случайным зерном.
+func NewSeeded[K comparable]() *Seeded[K] {
+	return &Seeded[K]{seed: maphash.MakeSeed()}
+}
+
+// Hash возвращает хэш значение ключа.
+func (h *Seeded[K]) Hash(key K) uint64 {
+	return maphash.Comparable(h.seed, key)
+}
+
+// Bits возвращает разрядность хэш значения.
+func (h *Seeded[K]) Bits() int {
+	return 64
+}
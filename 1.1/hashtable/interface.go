@@ -0,0 +1,35 @@
+package hashtable
+
+import "iter"
+
+// Interface представляет общий интерфейс хэш-таблиц:
+// Table, ConcurrentTable и таблиц с открытой адресацией.
+type Interface[K comparable, V any] interface {
+	// Len возвращает количество элементов в таблице.
+	Len() int
+
+	// Put записывает значение по ключу.
+	Put(key K, value V)
+
+	// Get возвращает значение по ключу и true, если элемент существует.
+	Get(key K) (V, bool)
+
+	// GetOrInsert возвращает значение существующего элемента и true
+	// или добавляет переданное значение и возвращает его и false.
+	GetOrInsert(key K, value V) (V, bool)
+
+	// Update заменяет значение по ключу результатом fn.
+	Update(key K, fn func(old V) V) V
+
+	// Delete удаляет элемент и возвращает его значение и true,
+	// если элемент существовал.
+	Delete(key K) (V, bool)
+
+	// All возвращает итератор по парам ключ-значение.
+	All() iter.Seq2[K, V]
+}
+
+var (
+	_ Interface[string, int] = (*Table[string, int])(nil)
+	_ Interface[string, int] = (*ConcurrentTable[string, int])(nil)
+)
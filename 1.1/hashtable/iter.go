@@ -0,0 +1,101 @@
+package hashtable
+
+import "iter"
+
+// Elements возвращает итератор по элементам таблицы
+// вместе с ключами их сегментов.
+//
+// Итерация проходит по списку сегментов и спискам их элементов,
+// а для упорядоченной таблицы — в порядке, заданном WithOrder.
+// Если таблица изменяется во время итерации, то:
+//   - элемент, удаленный до того, как итерация дошла до него, не выдается;
+//   - элемент, срок жизни которого истек, не выдается;
+//   - элемент, добавленный во время итерации, может быть выдан или нет,
+//     а в упорядоченной таблице не выдается;
+//   - остальные элементы выдаются ровно один раз.
+//
+// Пока итерация не завершена, таблица не переносит элементы
+// в массив сегментов другого размера и не меняет порядок
+// использования элементов.
+func (t *Table[K, V]) Elements() iter.Seq2[uint64, *Element[K, V]] {
+	return func(yield func(uint64, *Element[K, V]) bool) {
+		t.iterating++
+		defer func() { t.iterating-- }()
+
+		now := t.opts.clock.Now().UnixNano()
+
+		if t.opts.order != Unordered {
+			t.ordered(now, yield)
+			return
+		}
+
+		// Прохожусь по всем сегментам
+		for s := t.head; s != nil; s = s.next {
+			// Прохожусь по элементам списка сегмента,
+			// пропуская удаленные во время итерации
+			for e := s.list; e != nil; e = e.next {
+				if e.removed || e.expiredAt(now) {
+					continue
+				}
+
+				if !yield(s.key, e) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// ordered выдает элементы упорядоченной таблицы в порядке обхода.
+// Номера элементов растут вдоль порядка обхода, поэтому обход
+// заканчивается на первом элементе, поставленном в порядок после начала
+// итерации. Сравнивать с t.newest нельзя: если его удалить,
+// то ссылки удаленных элементов могут провести мимо него.
+func (t *Table[K, V]) ordered(now int64, yield func(uint64, *Element[K, V]) bool) {
+	end := t.enqueued
+
+	for e := t.oldest; e != nil && e.seq < end; e = e.after {
+		if e.removed || e.expiredAt(now) {
+			continue
+		}
+
+		_, i := t.locate(e.hash)
+		if !yield(i, e) {
+			return
+		}
+	}
+}
+
+// All возвращает итератор по парам ключ-значение таблицы.
+// Изменение таблицы во время итерации описано в Elements.
+func (t *Table[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for _, e := range t.Elements() {
+			if !yield(e.key, e.value) {
+				return
+			}
+		}
+	}
+}
+
+// Keys возвращает итератор по ключам таблицы.
+func (t *Table[K, V]) Keys() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for _, e := range t.Elements() {
+			if !yield(e.key) {
+				return
+			}
+		}
+	}
+}
+
+// Values возвращает итератор по значениям таблицы.
+func (t *Table[K, V]) Values() iter.Seq[V] {
+	return func(yield func(V) bool) {
+		for _, e := range t.Elements() {
+			if !yield(e.value) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,234 @@
+package hashtable
+
+import (
+	"strconv"
+	"testing"
+)
+
+// filled возвращает таблицу из n строк, которая переносит элементы:
+// итерация начинается, пока часть сегментов еще в old.
+func filled(t *testing.T, n int, opts ...Option[string, int]) *Table[string, int] {
+	t.Helper()
+
+	tbl := NewTable(FNV1a{}, append([]Option[string, int]{WithBits[string, int](2)}, opts...)...)
+
+	for i := 0; i < n || !tbl.Rehashing(); i++ {
+		tbl.Put(strconv.Itoa(i), i)
+	}
+
+	return tbl
+}
+
+// order возвращает ключи таблицы в порядке обхода.
+func order(tbl *Table[string, int]) []string {
+	var keys []string
+	for k := range tbl.Keys() {
+		keys = append(keys, k)
+	}
+
+	return keys
+}
+
+// frozen проверяет, что во время итерации таблица не переносит элементы.
+type frozen struct {
+	tbl       *Table[string, int]
+	bits      int
+	old       bool
+	evacuated int
+}
+
+func freeze(tbl *Table[string, int]) frozen {
+	return frozen{tbl: tbl, bits: tbl.Bits(), old: tbl.old != nil, evacuated: tbl.evacuated}
+}
+
+func (f frozen) check(t *testing.T) {
+	t.Helper()
+
+	if f.tbl.Bits() != f.bits || (f.tbl.old != nil) != f.old || f.tbl.evacuated != f.evacuated {
+		t.Fatalf("перенос во время итерации: разрядность %d -> %d, перенесено %d -> %d",
+			f.bits, f.tbl.Bits(), f.evacuated, f.tbl.evacuated)
+	}
+}
+
+func TestIterateDeleteCurrent(t *testing.T) {
+	for _, o := range []Order{Unordered, InsertionOrder, AccessOrder} {
+		t.Run(strconv.Itoa(int(o)), func(t *testing.T) {
+			tbl := filled(t, 500, WithOrder[string, int](o))
+			n := tbl.Len()
+			f := freeze(tbl)
+			seen := make(map[string]bool)
+
+			for k := range tbl.Keys() {
+				if seen[k] {
+					t.Fatalf("ключ %q выдан повторно", k)
+				}
+
+				seen[k] = true
+
+				if _, ok := tbl.Delete(k); !ok {
+					t.Fatalf("Delete(%q) не нашел выданный ключ", k)
+				}
+
+				f.check(t)
+			}
+
+			if len(seen) != n || tbl.Len() != 0 {
+				t.Errorf("выдано %d из %d, осталось %d", len(seen), n, tbl.Len())
+			}
+
+			checkTable(t, tbl, map[string]int{})
+		})
+	}
+}
+
+func TestIterateDeleteAhead(t *testing.T) {
+	for _, o := range []Order{Unordered, InsertionOrder} {
+		t.Run(strconv.Itoa(int(o)), func(t *testing.T) {
+			tbl := filled(t, 500, WithOrder[string, int](o))
+			keys := order(tbl)
+			f := freeze(tbl)
+			want := make(map[string]int)
+
+			// На первом элементе удаляю каждый второй из еще не выданных
+			var got []string
+			for k := range tbl.Keys() {
+				if len(got) == 0 {
+					for i := 1; i < len(keys); i += 2 {
+						tbl.Delete(keys[i])
+					}
+				}
+
+				got = append(got, k)
+				f.check(t)
+			}
+
+			for i := 0; i < len(keys); i += 2 {
+				want[keys[i]], _ = tbl.Get(keys[i])
+			}
+
+			if len(got) != len(want) {
+				t.Fatalf("выдано %d ключей, want %d", len(got), len(want))
+			}
+
+			for i, k := range got {
+				if k != keys[2*i] {
+					t.Fatalf("ключ %d: %q, want %q", i, k, keys[2*i])
+				}
+			}
+
+			checkTable(t, tbl, want)
+		})
+	}
+}
+
+func TestIterateInsert(t *testing.T) {
+	tbl := filled(t, 500)
+	f := freeze(tbl)
+
+	want := make(map[string]int)
+	for k, v := range tbl.All() {
+		want[k] = v
+	}
+
+	// Каждый шаг добавляет несколько строк, так что без остановки
+	// переноса таблица успела бы вырасти
+	seen := make(map[string]bool)
+	added := 0
+
+	for k, v := range tbl.All() {
+		if seen[k] {
+			t.Fatalf("ключ %q выдан повторно", k)
+		}
+
+		seen[k] = true
+
+		if _, ok := want[k]; !ok {
+			// Добавленный во время итерации элемент может быть выдан
+			want[k] = v
+			continue
+		}
+
+		for range 4 {
+			key := "new-" + strconv.Itoa(added)
+			tbl.Put(key, -added)
+			want[key] = -added
+			added++
+		}
+
+		f.check(t)
+	}
+
+	for k := range want {
+		if k[0] != 'n' && !seen[k] {
+			t.Fatalf("ключ %q пропущен", k)
+		}
+	}
+
+	checkTable(t, tbl, want)
+
+	// После итерации перенос продолжается
+	tbl.Put("after", 0)
+
+	if tbl.evacuated == f.evacuated && tbl.Bits() == f.bits {
+		t.Error("перенос не продолжился после итерации")
+	}
+}
+
+func TestIterateInsertOrdered(t *testing.T) {
+	for _, o := range []Order{InsertionOrder, AccessOrder} {
+		t.Run(strconv.Itoa(int(o)), func(t *testing.T) {
+			tbl := filled(t, 500, WithOrder[string, int](o))
+			keys := order(tbl)
+			last := keys[len(keys)-1]
+			want := make(map[string]int)
+
+			// Каждый выданный ключ заменяю новым, а на первом шаге
+			// удаляю и последний элемент, на котором итерация
+			// закончилась бы без добавлений
+			var got []string
+			for k, v := range tbl.All() {
+				if len(got) > len(keys) {
+					t.Fatalf("выдано больше %d ключей", len(keys))
+				}
+
+				got = append(got, k)
+
+				if len(got) == 1 {
+					tbl.Delete(last)
+					tbl.Put("new"+last, 0)
+					want["new"+last] = 0
+				}
+
+				if k != last {
+					tbl.Delete(k)
+					tbl.Put("new"+k, v)
+					want["new"+k] = v
+				}
+			}
+
+			if len(got) != len(keys)-1 {
+				t.Fatalf("выдано %d ключей, want %d", len(got), len(keys)-1)
+			}
+
+			for i, k := range got {
+				if k != keys[i] {
+					t.Fatalf("ключ %d: %q, want %q", i, k, keys[i])
+				}
+			}
+
+			checkTable(t, tbl, want)
+		})
+	}
+}
+
+func TestIterateBreak(t *testing.T) {
+	tbl := filled(t, 100)
+
+	for range tbl.All() {
+		break
+	}
+
+	if tbl.iterating != 0 {
+		t.Fatalf("iterating = %d после break", tbl.iterating)
+	}
+}
@@ -0,0 +1,202 @@
+package hashtable
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// ErrJSONFormat возвращается, если JSON не является
+// массивом строк или объектом.
+var ErrJSONFormat = errors.New("hashtable: ожидается JSON массив строк или объект")
+
+// ErrColumn возвращается, если номер столбца CSV меньше нуля.
+var ErrColumn = errors.New("hashtable: отрицательный номер столбца CSV")
+
+// LoadResult содержит итоги загрузки таблицы.
+type LoadResult struct {
+	Added      int           `json:"added"`      // Количество добавленных элементов
+	Duplicates int           `json:"duplicates"` // Количество пропущенных повторов
+	Elapsed    time.Duration `json:"elapsed_ns"` // Время загрузки
+}
+
+// loader добавляет элементы в таблицу и считает итоги загрузки.
+type loader[V any] struct {
+	t     Interface[string, V]
+	res   LoadResult
+	start time.Time
+}
+
+// newLoader возвращает загрузчик в таблицу t.
+func newLoader[V any](t Interface[string, V]) *loader[V] {
+	return &loader[V]{t: t, start: time.Now()}
+}
+
+// add добавляет элемент, если ключа еще нет в таблице.
+func (l *loader[V]) add(key string, value V) {
+	if _, found := l.t.GetOrInsert(key, value); found {
+		l.res.Duplicates++
+	} else {
+		l.res.Added++
+	}
+}
+
+// result возвращает итоги загрузки.
+func (l *loader[V]) result() LoadResult {
+	l.res.Elapsed = time.Since(l.start)
+	return l.res
+}
+
+// LoadLines добавляет в таблицу строки текста как ключи с нулевыми значениями.
+// Пробелы по краям строк отбрасываются, пустые строки пропускаются.
+// Ключи, которые уже есть в таблице, не изменяются и считаются повторами.
+func LoadLines[V any](t Interface[string, V], r io.Reader) (LoadResult, error) {
+	l := newLoader(t)
+
+	var zero V
+
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, 1<<20)
+
+	for sc.Scan() {
+		if key := strings.TrimSpace(sc.Text()); key != "" {
+			l.add(key, zero)
+		}
+	}
+
+	return l.result(), sc.Err()
+}
+
+// LoadCSV добавляет в таблицу значения столбца column (с нуля)
+// каждой записи CSV как ключи с нулевыми значениями.
+// Записи, в которых нет такого столбца, и пустые значения пропускаются.
+// Ключи, которые уже есть в таблице, не изменяются и считаются повторами.
+// Если column меньше нуля, то возвращается ErrColumn.
+func LoadCSV[V any](t Interface[string, V], r io.Reader, column int) (LoadResult, error) {
+	l := newLoader(t)
+
+	if column < 0 {
+		return l.result(), ErrColumn
+	}
+
+	var zero V
+
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.ReuseRecord = true
+
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return l.result(), err
+		}
+
+		if column < len(record) && record[column] != "" {
+			l.add(record[column], zero)
+		}
+	}
+
+	return l.result(), nil
+}
+
+// LoadJSON добавляет в таблицу содержимое JSON массива строк или объекта.
+// Строки массива добавляются как ключи с нулевыми значениями.
+// Поля объекта добавляются как ключи, а их значения декодируются в V;
+// если V — пустая структура, то значения полей не декодируются.
+// Ключи, которые уже есть в таблице, не изменяются и считаются повторами.
+// Массив или объект читается потоком, без загрузки всего JSON в память.
+func LoadJSON[V any](t Interface[string, V], r io.Reader) (LoadResult, error) {
+	l := newLoader(t)
+
+	dec := json.NewDecoder(r)
+
+	tok, err := dec.Token()
+	if err != nil {
+		return l.result(), err
+	}
+
+	switch tok {
+	case json.Delim('['):
+		err = loadJSONArray(l, dec)
+	case json.Delim('{'):
+		err = loadJSONObject(l, dec)
+	default:
+		err = ErrJSONFormat
+	}
+
+	if err != nil {
+		return l.result(), err
+	}
+
+	// Закрывающая скобка массива или объекта
+	if _, err := dec.Token(); err != nil {
+		return l.result(), err
+	}
+
+	return l.result(), nil
+}
+
+// loadJSONArray добавляет строки JSON массива как ключи.
+func loadJSONArray[V any](l *loader[V], dec *json.Decoder) error {
+	var zero V
+
+	for dec.More() {
+		// null декодируется без ошибки, поэтому читаю строку по указателю
+		var key *string
+		if err := dec.Decode(&key); err != nil {
+			return fmt.Errorf("%w: %w", ErrJSONFormat, err)
+		}
+
+		if key == nil {
+			return ErrJSONFormat
+		}
+
+		l.add(*key, zero)
+	}
+
+	return nil
+}
+
+// loadJSONObject добавляет поля JSON объекта как ключи со значениями.
+func loadJSONObject[V any](l *loader[V], dec *json.Decoder) error {
+	var zero V
+	_, set := any(zero).(struct{})
+
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+
+		key, ok := tok.(string)
+		if !ok {
+			return ErrJSONFormat
+		}
+
+		var value V
+
+		if set {
+			var skip json.RawMessage
+			err = dec.Decode(&skip)
+		} else {
+			err = dec.Decode(&value)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		l.add(key, value)
+	}
+
+	return nil
+}
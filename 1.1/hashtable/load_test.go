@@ -0,0 +1,239 @@
+package hashtable
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// checkLoad проверяет итоги загрузки и содержимое таблицы.
+func checkLoad(t *testing.T, tbl *Table[string, int], res LoadResult, added, duplicates int, want map[string]int) {
+	t.Helper()
+
+	if res.Added != added || res.Duplicates != duplicates {
+		t.Errorf("добавлено %d, повторов %d, want %d, %d", res.Added, res.Duplicates, added, duplicates)
+	}
+
+	checkTable(t, tbl, want)
+}
+
+func TestLoadLines(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		added      int
+		duplicates int
+		want       map[string]int
+	}{
+		{"пусто", "", 0, 0, map[string]int{}},
+		{"пробелы и пустые строки", "  a \n\n\t\nb\r\n", 2, 0, map[string]int{"a": 0, "b": 0}},
+		{"повторы", "a\nb\na\n a\nb", 2, 3, map[string]int{"a": 0, "b": 0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tbl := NewTable[string, int](FNV1a{})
+
+			res, err := LoadLines(tbl, strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			checkLoad(t, tbl, res, tt.added, tt.duplicates, tt.want)
+		})
+	}
+
+	// Уже существующий ключ не изменяется
+	tbl := NewTable[string, int](FNV1a{})
+	tbl.Put("a", 1)
+
+	res, err := LoadLines(tbl, strings.NewReader("a\nb"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checkLoad(t, tbl, res, 1, 1, map[string]int{"a": 1, "b": 0})
+}
+
+func TestLoadCSV(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		column     int
+		err        bool
+		added      int
+		duplicates int
+		want       map[string]int
+	}{
+		{
+			name:   "первый столбец",
+			input:  "a,1\nb,2\n",
+			column: 0,
+			added:  2,
+			want:   map[string]int{"a": 0, "b": 0},
+		},
+		{
+			name:       "повторы",
+			input:      "x,a\ny,b\nz,a\n",
+			column:     1,
+			added:      2,
+			duplicates: 1,
+			want:       map[string]int{"a": 0, "b": 0},
+		},
+		{
+			name:   "записи без столбца пропускаются",
+			input:  "a,1\nb\nc,3,x\n",
+			column: 1,
+			added:  2,
+			want:   map[string]int{"1": 0, "3": 0},
+		},
+		{
+			name:   "пустые значения пропускаются",
+			input:  "a,\n,b\n\"\",c\n",
+			column: 0,
+			added:  1,
+			want:   map[string]int{"a": 0},
+		},
+		{
+			name:   "столбца нет ни в одной записи",
+			input:  "a,b\nc,d\n",
+			column: 5,
+			want:   map[string]int{},
+		},
+		{
+			name:   "отрицательный столбец",
+			input:  "a,b\n",
+			column: -1,
+			err:    true,
+			want:   map[string]int{},
+		},
+		{
+			name:   "ошибка разбора",
+			input:  "a\n\"b\n",
+			column: 0,
+			err:    true,
+			added:  1,
+			want:   map[string]int{"a": 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tbl := NewTable[string, int](FNV1a{})
+
+			res, err := LoadCSV(tbl, strings.NewReader(tt.input), tt.column)
+			if (err != nil) != tt.err {
+				t.Fatalf("LoadCSV() = %v, want ошибка %v", err, tt.err)
+			}
+
+			if tt.column < 0 && !errors.Is(err, ErrColumn) {
+				t.Errorf("LoadCSV() = %v, want %v", err, ErrColumn)
+			}
+
+			checkLoad(t, tbl, res, tt.added, tt.duplicates, tt.want)
+		})
+	}
+}
+
+func TestLoadJSON(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		err        error // nil, если ошибки нет; errAny, если ошибка любая
+		added      int
+		duplicates int
+		want       map[string]int
+	}{
+		{
+			name:  "массив",
+			input: `["a", "b", ""]`,
+			added: 3,
+			want:  map[string]int{"a": 0, "b": 0, "": 0},
+		},
+		{
+			name:       "массив с повторами",
+			input:      `["a", "b", "a", "a"]`,
+			added:      2,
+			duplicates: 2,
+			want:       map[string]int{"a": 0, "b": 0},
+		},
+		{
+			name:  "объект",
+			input: `{"a": 1, "b": 2}`,
+			added: 2,
+			want:  map[string]int{"a": 1, "b": 2},
+		},
+		{
+			name:       "объект с повторами",
+			input:      `{"a": 1, "b": 2, "a": 3}`,
+			added:      2,
+			duplicates: 1,
+			want:       map[string]int{"a": 1, "b": 2},
+		},
+		{
+			name:  "пустой массив",
+			input: `[]`,
+			want:  map[string]int{},
+		},
+		{
+			name:  "число в массиве",
+			input: `["a", 1, "b"]`,
+			err:   ErrJSONFormat,
+			added: 1,
+			want:  map[string]int{"a": 0},
+		},
+		{
+			name:  "null в массиве",
+			input: `["a", null]`,
+			err:   ErrJSONFormat,
+			added: 1,
+			want:  map[string]int{"a": 0},
+		},
+		{
+			name:  "вложенный массив",
+			input: `[["a"]]`,
+			err:   ErrJSONFormat,
+			want:  map[string]int{},
+		},
+		{
+			name:  "строка вместо массива",
+			input: `"a"`,
+			err:   ErrJSONFormat,
+			want:  map[string]int{},
+		},
+		{
+			name:  "значение объекта другого типа",
+			input: `{"a": 1, "b": "x"}`,
+			err:   errAny,
+			added: 1,
+			want:  map[string]int{"a": 1},
+		},
+		{
+			name:  "незакрытый массив",
+			input: `["a"`,
+			err:   errAny,
+			added: 1,
+			want:  map[string]int{"a": 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tbl := NewTable[string, int](FNV1a{})
+
+			res, err := LoadJSON(tbl, strings.NewReader(tt.input))
+
+			switch {
+			case tt.err == nil && err != nil, tt.err != nil && err == nil:
+				t.Fatalf("LoadJSON() = %v, want %v", err, tt.err)
+			case tt.err != nil && tt.err != errAny && !errors.Is(err, tt.err):
+				t.Fatalf("LoadJSON() = %v, want %v", err, tt.err)
+			}
+
+			checkLoad(t, tbl, res, tt.added, tt.duplicates, tt.want)
+		})
+	}
+}
+
+// errAny обозначает в тестах ожидаемую ошибку любого вида.
+var errAny = errors.New("любая ошибка")
@@ -0,0 +1,56 @@
+package hashtable
+
+import (
+	"strings"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+// WithNormalizer задает функции нормализации ключа,
+// которые применяются по порядку к каждому ключу перед вычислением
+// хэш значения и сравнением, поэтому ключи с одинаковой нормальной
+// формой считаются одним ключом. Таблица хранит ключи в нормальной форме.
+func WithNormalizer[K comparable, V any](fns ...func(K) K) Option[K, V] {
+	return func(o *options[K, V]) {
+		if len(fns) == 0 {
+			o.normalize = nil
+			return
+		}
+
+		o.normalize = func(key K) K {
+			for _, fn := range fns {
+				key = fn(key)
+			}
+
+			return key
+		}
+	}
+}
+
+// TrimSpace отбрасывает пробелы по краям строки.
+func TrimSpace(s string) string {
+	return strings.TrimSpace(s)
+}
+
+// FoldCase приводит строку к форме без учета регистра (case folding),
+// например "Ёж" и "ёж" получают одну форму.
+func FoldCase(s string) string {
+	// Caser хранит состояние, поэтому создаю его на каждый вызов
+	return cases.Fold().String(s)
+}
+
+// NFC приводит строку к нормальной форме Unicode NFC
+// (каноническая декомпозиция с последующей композицией),
+// например "е" с комбинируемым умляутом становится "ё".
+func NFC(s string) string {
+	return norm.NFC.String(s)
+}
+
+// NFKC приводит строку к нормальной форме Unicode NFKC
+// (совместимая декомпозиция с последующей композицией),
+// которая дополнительно объединяет совместимые символы,
+// например лигатуры и полноширинные цифры.
+func NFKC(s string) string {
+	return norm.NFKC.String(s)
+}
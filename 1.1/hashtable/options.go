@@ -0,0 +1,118 @@
+package hashtable
+
+import "time"
+
+// defaultBits задает разрядность ключа сегмента по умолчанию.
+const defaultBits = 8
+
+// maxBits ограничивает разрядность ключа сегмента,
+// так как массив сегментов содержит 2^bits звеньев.
+const maxBits = 32
+
+// defaultShardBits задает разрядность номера сегмента ConcurrentTable
+// по умолчанию, совпадающую с разрядностью Pearson8Hash.
+const defaultShardBits = 8
+
+// Коэффициенты заполнения по умолчанию.
+const (
+	defaultGrowLoad   = 1.0
+	defaultShrinkLoad = 0.25
+)
+
+// options содержит настройки таблицы.
+type options[K comparable, V any] struct {
+	bits       int           // Начальная разрядность ключа сегмента
+	maxBits    int           // Максимальная разрядность ключа сегмента
+	growLoad   float64       // Коэффициент заполнения, при превышении которого таблица растет
+	shrinkLoad float64       // Коэффициент заполнения, ниже которого таблица уменьшается
+	shardBits  int           // Разрядность номера сегмента ConcurrentTable
+	normalize  func(K) K     // Функция нормализации ключа или nil
+	order      Order         // Порядок обхода элементов
+	capacity   int           // Ограничение количества элементов, 0 без ограничения
+	evict      func(K, V)    // Функция удаления при переполнении или nil
+	ttl        time.Duration // Срок жизни элементов по умолчанию, 0 без истечения
+	clock      Clock         // Часы для срока жизни элементов
+	expire     func(K, V)    // Функция удаления истекших элементов или nil
+}
+
+// Option представляет настройку таблицы с ключами K и значениями V.
+// Настройка с другими типами ключа или значения не подходит таблице,
+// поэтому такая ошибка обнаруживается при компиляции.
+type Option[K comparable, V any] func(*options[K, V])
+
+// WithBits задает начальную разрядность ключа сегмента:
+// таблица использует младшие bits бит хэш значения
+// как индекс в массиве из 2^bits сегментов.
+// Значение ограничивается разрядностью хэш функции.
+// Таблица не уменьшается меньше начальной разрядности.
+func WithBits[K comparable, V any](bits int) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.bits = bits
+	}
+}
+
+// WithGrowLoad задает коэффициент заполнения (элементов на сегмент),
+// при превышении которого массив сегментов увеличивается вдвое.
+// Значение 0 отключает рост таблицы.
+func WithGrowLoad[K comparable, V any](load float64) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.growLoad = load
+	}
+}
+
+// WithShrinkLoad задает коэффициент заполнения,
+// ниже которого массив сегментов уменьшается вдвое.
+// Значение должно быть меньше половины коэффициента роста,
+// иначе оно уменьшается до четверти коэффициента роста.
+// Значение 0 отключает уменьшение таблицы.
+func WithShrinkLoad[K comparable, V any](load float64) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.shrinkLoad = load
+	}
+}
+
+// WithShardBits задает разрядность номера сегмента ConcurrentTable:
+// таблица делится на 2^bits сегментов, каждый со своей блокировкой.
+// Значение ограничивается разрядностью хэш функции.
+// Table эту настройку не использует.
+func WithShardBits[K comparable, V any](bits int) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.shardBits = bits
+	}
+}
+
+// newOptions возвращает настройки таблицы
+// для хэш функции указанной разрядности.
+func newOptions[K comparable, V any](hashBits int, opts []Option[K, V]) options[K, V] {
+	o := options[K, V]{
+		bits:       defaultBits,
+		growLoad:   defaultGrowLoad,
+		shrinkLoad: defaultShrinkLoad,
+		shardBits:  defaultShardBits,
+		clock:      systemClock{},
+	}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	o.maxBits = min(hashBits, maxBits)
+	o.bits = min(max(o.bits, 0), o.maxBits)
+	o.shardBits = min(max(o.shardBits, 0), o.maxBits)
+	o.growLoad = max(o.growLoad, 0)
+	o.shrinkLoad = max(o.shrinkLoad, 0)
+	o.capacity = max(o.capacity, 0)
+	o.ttl = max(o.ttl, 0)
+
+	if o.capacity > 0 && o.order == Unordered {
+		o.order = InsertionOrder
+	}
+
+	// После роста коэффициент заполнения падает вдвое,
+	// он не должен сразу оказаться ниже порога уменьшения
+	if o.growLoad > 0 && o.shrinkLoad*2 >= o.growLoad {
+		o.shrinkLoad = o.growLoad / 4
+	}
+
+	return o
+}
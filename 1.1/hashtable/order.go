@@ -0,0 +1,159 @@
+package hashtable
+
+// Order задает порядок обхода элементов таблицы.
+type Order int
+
+const (
+	// Unordered обходит элементы по списку сегментов (по умолчанию).
+	Unordered Order = iota
+	// InsertionOrder обходит элементы в порядке добавления.
+	// Запись значения существующего ключа порядок не меняет.
+	InsertionOrder
+	// AccessOrder обходит элементы от давно использованных
+	// к недавно использованным (LRU): добавление, Put, Get,
+	// GetOrInsert, Update и Find переносят элемент в конец порядка.
+	AccessOrder
+)
+
+// WithOrder задает порядок обхода элементов таблицы.
+// Для упорядоченной таблицы элементы дополнительно
+// связаны в список в этом порядке.
+// ConcurrentTable эту настройку не использует.
+func WithOrder[K comparable, V any](order Order) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.order = order
+	}
+}
+
+// WithCapacity ограничивает количество элементов таблицы:
+// при добавлении элемента сверх capacity удаляется первый
+// в порядке обхода элемент. Если порядок не задан,
+// то используется InsertionOrder. Значение 0 снимает ограничение.
+// ConcurrentTable эту настройку не использует.
+func WithCapacity[K comparable, V any](capacity int) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.capacity = capacity
+	}
+}
+
+// WithEvict задает функцию, которая вызывается для каждого элемента,
+// удаленного из-за ограничения WithCapacity, после его удаления.
+func WithEvict[K comparable, V any](fn func(key K, value V)) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.evict = fn
+	}
+}
+
+// NewLRU возвращает пустую таблицу не более чем из capacity элементов,
+// которая при переполнении удаляет давно использованный элемент.
+func NewLRU[K comparable, V any](hash Hasher[K], capacity int, opts ...Option[K, V]) *Table[K, V] {
+	opts = append(opts[:len(opts):len(opts)], WithOrder[K, V](AccessOrder), WithCapacity[K, V](capacity))
+	return NewTable[K, V](hash, opts...)
+}
+
+// Order возвращает порядок обхода элементов таблицы.
+func (t *Table[K, V]) Order() Order {
+	return t.opts.order
+}
+
+// Capacity возвращает ограничение количества элементов
+// или 0, если оно не задано.
+func (t *Table[K, V]) Capacity() int {
+	return t.opts.capacity
+}
+
+// Oldest возвращает первый в порядке обхода элемент упорядоченной таблицы.
+// Если таблица пуста или не упорядочена, то возвращает nil.
+func (t *Table[K, V]) Oldest() *Element[K, V] {
+	return t.oldest
+}
+
+// Newest возвращает последний в порядке обхода элемент упорядоченной таблицы.
+// Если таблица пуста или не упорядочена, то возвращает nil.
+func (t *Table[K, V]) Newest() *Element[K, V] {
+	return t.newest
+}
+
+// Peek возвращает значение по ключу и true, если элемент существует,
+// не изменяя таблицу: порядок обхода не меняется,
+// а истекший элемент не возвращается, но и не удаляется.
+func (t *Table[K, V]) Peek(key K) (V, bool) {
+	if _, _, e := t.lookup(t.normalize(key)); e != nil && !t.expired(e) {
+		return e.value, true
+	}
+
+	var zero V
+	return zero, false
+}
+
+// enqueue добавляет элемент в конец порядка обхода.
+func (t *Table[K, V]) enqueue(e *Element[K, V]) {
+	if t.opts.order == Unordered {
+		return
+	}
+
+	e.before = t.newest
+	e.after = nil
+	e.seq = t.enqueued
+	t.enqueued++
+
+	if t.newest == nil {
+		t.oldest = e
+	} else {
+		t.newest.after = e
+	}
+
+	t.newest = e
+}
+
+// dequeue удаляет элемент из порядка обхода.
+func (t *Table[K, V]) dequeue(e *Element[K, V]) {
+	if t.opts.order == Unordered {
+		return
+	}
+
+	if e.before == nil {
+		t.oldest = e.after
+	} else {
+		e.before.after = e.after
+	}
+
+	if e.after == nil {
+		t.newest = e.before
+	} else {
+		e.after.before = e.before
+	}
+
+	// Ссылку на следующий элемент сохраняю,
+	// чтобы итерация, стоящая на этом элементе, могла продолжиться
+	e.before = nil
+}
+
+// touch переносит использованный элемент в конец порядка обхода,
+// если таблица упорядочена по использованию.
+// Во время итерации порядок не меняется, чтобы элементы
+// не были пропущены или выданы повторно.
+func (t *Table[K, V]) touch(e *Element[K, V]) {
+	if t.opts.order != AccessOrder || t.iterating > 0 || e == t.newest {
+		return
+	}
+
+	t.dequeue(e)
+	t.enqueue(e)
+}
+
+// evictExcess удаляет первые в порядке обхода элементы,
+// пока их количество превышает ограничение.
+func (t *Table[K, V]) evictExcess() {
+	if t.opts.capacity == 0 {
+		return
+	}
+
+	for t.len > t.opts.capacity && t.oldest != nil {
+		e := t.remove(t.oldest.hash, t.oldest.key)
+
+		if t.evict != nil {
+			t.evict(e.key, e.value)
+		}
+	}
+}
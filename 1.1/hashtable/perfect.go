@@ -0,0 +1,364 @@
+package hashtable
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"math/bits"
+	"math/rand/v2"
+	"slices"
+)
+
+// Perfect представляет минимальную совершенную хэш функцию
+// неизменяемого набора строк: каждая строка набора получает свой
+// номер от 0 до n-1 без коллизий, поэтому поиск занимает O(1)
+// и проверяет ровно одну строку.
+//
+// Функция строится методом CHD (hash, displace and compress):
+// строки раскладываются по корзинам, и для каждой корзины,
+// начиная с самых больших, подбирается смещение, при котором
+// все ее строки попадают в свободные ячейки.
+// Хэш значение строки вычисляется с таблицей перестановки
+// байт, как в Pearson8Hash, но случайной для каждой попытки построения.
+//
+// Perfect реализует Hasher[string]: с WithBits(p.Bits()) и без роста
+// таблица получает не больше одного элемента в сегменте.
+type Perfect struct {
+	table  [256]byte // Перестановка байт для хэш функции
+	disp   []uint32  // Смещение каждой корзины
+	keys   []string  // Строки набора по их номерам
+	bucket uint64    // Количество корзин
+}
+
+// perfectLoad задает среднее количество строк в корзине.
+const perfectLoad = 3
+
+// maxPerfectAttempts ограничивает количество попыток построения
+// с новой таблицей перестановки.
+const maxPerfectAttempts = 32
+
+// Ошибки построения совершенной хэш функции.
+var (
+	ErrDuplicateKey = errors.New("hashtable: строка повторяется в наборе")
+	ErrPerfectBuild = errors.New("hashtable: не удалось построить совершенную хэш функцию")
+)
+
+// BuildPerfect строит минимальную совершенную хэш функцию набора строк.
+// Строки не должны повторяться, иначе возвращается ErrDuplicateKey.
+func BuildPerfect(keys []string) (*Perfect, error) {
+	seen := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		if _, ok := seen[key]; ok {
+			return nil, ErrDuplicateKey
+		}
+
+		seen[key] = struct{}{}
+	}
+
+	rng := rand.New(rand.NewPCG(uint64(len(keys)), 0x9E3779B97F4A7C15))
+
+	for range maxPerfectAttempts {
+		p := &Perfect{bucket: uint64(max((len(keys)+perfectLoad-1)/perfectLoad, 1))}
+
+		for i, b := range rng.Perm(256) {
+			p.table[i] = byte(b)
+		}
+
+		if p.build(keys) {
+			return p, nil
+		}
+	}
+
+	return nil, ErrPerfectBuild
+}
+
+// build подбирает смещения корзин.
+// Возвращает false, если для какой-то корзины смещение не найдено
+// или хэш значения двух строк совпали.
+func (p *Perfect) build(keys []string) bool {
+	n := uint64(len(keys))
+
+	// Раскладываю строки по корзинам
+	hashes := make([]uint64, len(keys))
+	buckets := make([][]int, p.bucket)
+	seen := make(map[uint64]struct{}, len(keys))
+
+	for i, key := range keys {
+		h := p.hash(key)
+		if _, ok := seen[h]; ok {
+			return false
+		}
+
+		seen[h] = struct{}{}
+		hashes[i] = h
+
+		b := (h >> 32) % p.bucket
+		buckets[b] = append(buckets[b], i)
+	}
+
+	// Большие корзины размещаю первыми, пока свободных ячеек много
+	order := make([]int, len(buckets))
+	for i := range order {
+		order[i] = i
+	}
+
+	slices.SortStableFunc(order, func(a, b int) int {
+		return len(buckets[b]) - len(buckets[a])
+	})
+
+	p.disp = make([]uint32, p.bucket)
+	p.keys = make([]string, n)
+
+	taken := make([]bool, n)
+	slots := make([]uint64, 0, perfectLoad*4)
+
+	for _, b := range order {
+		if len(buckets[b]) == 0 {
+			break
+		}
+
+		found := false
+
+		// Перебираю смещения d = d1*n + d0, пока все строки корзины
+		// не попадут в разные свободные ячейки
+		for d := uint64(0); d < min(n*n, 1<<32); d++ {
+			slots = slots[:0]
+
+			for _, i := range buckets[b] {
+				s := slot(hashes[i], d, n)
+				if taken[s] || slices.Contains(slots, s) {
+					break
+				}
+
+				slots = append(slots, s)
+			}
+
+			if len(slots) == len(buckets[b]) {
+				for j, i := range buckets[b] {
+					taken[slots[j]] = true
+					p.keys[slots[j]] = keys[i]
+				}
+
+				p.disp[b] = uint32(d)
+				found = true
+
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Len возвращает количество строк набора.
+func (p *Perfect) Len() int {
+	return len(p.keys)
+}
+
+// Keys возвращает строки набора в порядке их номеров.
+func (p *Perfect) Keys() []string {
+	return slices.Clone(p.keys)
+}
+
+// Index возвращает номер строки и true, если строка есть в наборе.
+func (p *Perfect) Index(key string) (int, bool) {
+	if len(p.keys) == 0 {
+		return 0, false
+	}
+
+	i := p.Hash(key)
+	if p.keys[i] != key {
+		return 0, false
+	}
+
+	return int(i), true
+}
+
+// Hash возвращает номер строки набора от 0 до Len()-1.
+// Для строки не из набора возвращает произвольный номер в этих пределах.
+func (p *Perfect) Hash(key string) uint64 {
+	n := uint64(len(p.keys))
+	if n == 0 {
+		return 0
+	}
+
+	h := p.hash(key)
+	return slot(h, uint64(p.disp[(h>>32)%p.bucket]), n)
+}
+
+// Bits возвращает разрядность номера строки.
+func (p *Perfect) Bits() int {
+	return bits.Len(uint(max(len(p.keys)-1, 0)))
+}
+
+// hash возвращает 64-битное хэш значение строки: каждый байт
+// заменяется по таблице перестановки, как в Pearson8Hash,
+// и смешивается с состоянием умножением, как в FNV1a.
+func (p *Perfect) hash(key string) uint64 {
+	const (
+		offset = 14695981039346656037
+		prime  = 1099511628211
+	)
+
+	h := uint64(offset)
+
+	for i := 0; i < len(key); i++ {
+		h ^= uint64(p.table[byte(h)^key[i]])
+		h *= prime
+	}
+
+	// Перемешиваю биты, чтобы старшие и младшие половины не зависели друг от друга
+	h ^= h >> 33
+	h *= 0xFF51AFD7ED558CCD
+	h ^= h >> 33
+	h *= 0xC4CEB9FE1A85EC53
+	h ^= h >> 33
+
+	return h
+}
+
+// slot возвращает ячейку строки с хэш значением h при смещении корзины d
+// среди n ячеек: (f1 + d0*f2 + d1) mod n, где d = d1*n + d0.
+// При переборе d сначала меняется d0, поэтому строки разных корзин
+// проходят ячейки с разным шагом f2 и не скапливаются в соседних ячейках.
+func slot(h, d, n uint64) uint64 {
+	f1 := uint64(uint32(h)) % n
+	f2 := (h>>16 ^ h>>48) % n
+	d0, d1 := d%n, d/n
+
+	return (f1 + d0*f2 + d1) % n
+}
+
+// Формат совершенной хэш функции:
+//
+//	magic    [4]byte   "PHSH"
+//	version  uint8
+//	table    [256]byte перестановка байт
+//	keys     uvarint   количество строк
+//	buckets  uvarint   количество корзин
+//	смещения корзин: uvarint
+//	строки в порядке номеров: uvarint длина и байты
+//	checksum uint32    CRC-32 (Castagnoli) всех предыдущих байт, big endian
+const (
+	perfectMagic   = "PHSH"
+	perfectVersion = 1
+)
+
+// MarshalBinary возвращает совершенную хэш функцию вместе с набором строк
+// в компактном двоичном виде.
+func (p *Perfect) MarshalBinary() ([]byte, error) {
+	buf := make([]byte, 0, len(perfectMagic)+1+len(p.table)+2*binary.MaxVarintLen64+len(p.disp)*2)
+
+	buf = append(buf, perfectMagic...)
+	buf = append(buf, perfectVersion)
+	buf = append(buf, p.table[:]...)
+	buf = binary.AppendUvarint(buf, uint64(len(p.keys)))
+	buf = binary.AppendUvarint(buf, p.bucket)
+
+	for _, d := range p.disp {
+		buf = binary.AppendUvarint(buf, uint64(d))
+	}
+
+	for _, key := range p.keys {
+		buf = binary.AppendUvarint(buf, uint64(len(key)))
+		buf = append(buf, key...)
+	}
+
+	return binary.BigEndian.AppendUint32(buf, crc32.Checksum(buf, castagnoli)), nil
+}
+
+// UnmarshalBinary восстанавливает совершенную хэш функцию,
+// записанную MarshalBinary.
+// Если данные повреждены, то функция не изменяется.
+func (p *Perfect) UnmarshalBinary(data []byte) error {
+	header := len(perfectMagic) + 1 + len(p.table)
+	if len(data) < header+4 || string(data[:len(perfectMagic)]) != perfectMagic {
+		return ErrFormat
+	}
+
+	body := data[:len(data)-4]
+	if crc32.Checksum(body, castagnoli) != binary.BigEndian.Uint32(data[len(body):]) {
+		return ErrChecksum
+	}
+
+	if body[len(perfectMagic)] != perfectVersion {
+		return ErrVersion
+	}
+
+	var q Perfect
+	copy(q.table[:], body[len(perfectMagic)+1:])
+	rest := body[header:]
+
+	next := func() (uint64, error) {
+		v, n := binary.Uvarint(rest)
+		if n <= 0 {
+			return 0, ErrFormat
+		}
+
+		rest = rest[n:]
+
+		return v, nil
+	}
+
+	n, err := next()
+	if err != nil {
+		return err
+	}
+
+	if q.bucket, err = next(); err != nil {
+		return err
+	}
+
+	// Каждая корзина и строка занимает хотя бы один байт
+	if q.bucket == 0 || q.bucket > uint64(len(rest)) || n > uint64(len(rest)) {
+		return ErrFormat
+	}
+
+	q.disp = make([]uint32, q.bucket)
+	for i := range q.disp {
+		d, err := next()
+		if err != nil {
+			return err
+		}
+
+		if d > 1<<32-1 {
+			return ErrFormat
+		}
+
+		q.disp[i] = uint32(d)
+	}
+
+	q.keys = make([]string, n)
+	for i := range q.keys {
+		size, err := next()
+		if err != nil {
+			return err
+		}
+
+		if size > uint64(len(rest)) {
+			return ErrFormat
+		}
+
+		q.keys[i] = string(rest[:size])
+		rest = rest[size:]
+	}
+
+	if len(rest) != 0 {
+		return ErrFormat
+	}
+
+	// Каждая строка должна получать свой номер
+	for i, key := range q.keys {
+		if q.Hash(key) != uint64(i) {
+			return ErrFormat
+		}
+	}
+
+	*p = q
+
+	return nil
+}
@@ -0,0 +1,170 @@
+package hashtable
+
+import (
+	"bytes"
+	"errors"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+// checkPerfect проверяет, что каждая строка набора получает
+// свой номер от 0 до n-1, а строки не из набора не находятся.
+func checkPerfect(t *testing.T, p *Perfect, keys []string) {
+	t.Helper()
+
+	n := len(keys)
+
+	if p.Len() != n {
+		t.Fatalf("Len() = %d, want %d", p.Len(), n)
+	}
+
+	if n > 0 && 1<<p.Bits() < n {
+		t.Fatalf("Bits() = %d, меньше разрядности %d номеров", p.Bits(), n)
+	}
+
+	index := p.Keys()
+	used := make([]bool, n)
+
+	for _, key := range keys {
+		i, ok := p.Index(key)
+		if !ok || i < 0 || i >= n {
+			t.Fatalf("Index(%q) = %d, %v, want номер от 0 до %d", key, i, ok, n-1)
+		}
+
+		if used[i] {
+			t.Fatalf("Index(%q) = %d, номер уже занят", key, i)
+		}
+
+		used[i] = true
+
+		if index[i] != key || p.Hash(key) != uint64(i) {
+			t.Fatalf("Keys()[%d] = %q, Hash(%q) = %d", i, index[i], key, p.Hash(key))
+		}
+	}
+
+	for _, key := range []string{"", "missing", "key-", "key-" + strconv.Itoa(n)} {
+		if i, ok := p.Index(key); ok {
+			t.Fatalf("Index(%q) = %d, true для строки не из набора", key, i)
+		}
+
+		if h := p.Hash(key); n > 0 && h >= uint64(n) {
+			t.Fatalf("Hash(%q) = %d, want меньше %d", key, h, n)
+		}
+	}
+}
+
+func TestPerfect(t *testing.T) {
+	for _, n := range []int{0, 1, 2, 3, 5000} {
+		t.Run(strconv.Itoa(n), func(t *testing.T) {
+			keys := make([]string, n)
+			for i := range keys {
+				keys[i] = "key-" + strconv.Itoa(i)
+			}
+
+			p, err := BuildPerfect(keys)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			checkPerfect(t, p, keys)
+
+			// В таблице без роста каждый сегмент содержит не больше одного элемента
+			tbl := NewTable[string, int](p, WithBits[string, int](p.Bits()), WithGrowLoad[string, int](0))
+			want := make(map[string]int, n)
+
+			for i, key := range keys {
+				tbl.Put(key, i)
+				want[key] = i
+			}
+
+			if st := tbl.Stats(); st.LongestChain > 1 {
+				t.Errorf("LongestChain = %d, want не больше 1", st.LongestChain)
+			}
+
+			checkTable(t, tbl, want)
+
+			data, err := p.MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var q Perfect
+			if err := q.UnmarshalBinary(data); err != nil {
+				t.Fatalf("UnmarshalBinary: %v", err)
+			}
+
+			checkPerfect(t, &q, keys)
+
+			if !slices.Equal(q.Keys(), p.Keys()) {
+				t.Errorf("Keys() после восстановления %v, want %v", q.Keys(), p.Keys())
+			}
+		})
+	}
+}
+
+func TestPerfectDuplicate(t *testing.T) {
+	if _, err := BuildPerfect([]string{"a", "b", "a"}); !errors.Is(err, ErrDuplicateKey) {
+		t.Errorf("BuildPerfect() = %v, want %v", err, ErrDuplicateKey)
+	}
+}
+
+func TestPerfectCorrupt(t *testing.T) {
+	keys := []string{"GET", "POST", "PUT", "DELETE"}
+
+	p, err := BuildPerfect(keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	valid, _ := p.MarshalBinary()
+	body := string(valid[:len(valid)-4])
+
+	// Строки, переставленные между номерами, записываются
+	// с верной контрольной суммой, но не соответствуют смещениям
+	swapped := *p
+	swapped.keys = p.Keys()
+	swapped.keys[0], swapped.keys[1] = swapped.keys[1], swapped.keys[0]
+	moved, _ := swapped.MarshalBinary()
+
+	flipped := []byte(body)
+	flipped[len(flipped)-1] ^= 1
+
+	version := []byte(body)
+	version[len(perfectMagic)] = perfectVersion + 1
+
+	tests := []struct {
+		name string
+		data []byte
+		err  error
+	}{
+		{"пусто", nil, ErrFormat},
+		{"короткие данные", valid[:10], ErrFormat},
+		{"заголовок", append([]byte("XXXX"), valid[4:]...), ErrFormat},
+		{"контрольная сумма", append(flipped, valid[len(body):]...), ErrChecksum},
+		{"версия", signed(string(version)), ErrVersion},
+		{"обрезана строка", signed(body[:len(body)-1]), ErrFormat},
+		{"лишний байт", signed(body + "x"), ErrFormat},
+		{"строки переставлены", moved, ErrFormat},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var q Perfect
+			if err := q.UnmarshalBinary(valid); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := q.UnmarshalBinary(tt.data); !errors.Is(err, tt.err) {
+				t.Fatalf("UnmarshalBinary() = %v, want %v", err, tt.err)
+			}
+
+			// При ошибке функция не изменяется
+			if data, _ := q.MarshalBinary(); !bytes.Equal(data, valid) {
+				t.Error("функция изменилась после ошибки")
+			}
+
+			checkPerfect(t, &q, keys)
+		})
+	}
+}
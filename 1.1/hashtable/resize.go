@@ -0,0 +1,88 @@
+package hashtable
+
+// rehashStep задает количество сегментов,
+// переносимых при каждом изменении таблицы.
+const rehashStep = 2
+
+// Rehashing возвращает true, если таблица
+// переносит элементы в массив сегментов другого размера.
+func (t *Table[K, V]) Rehashing() bool {
+	return t.old != nil
+}
+
+// grow начинает увеличение массива сегментов,
+// если коэффициент заполнения превысил порог роста.
+func (t *Table[K, V]) grow() {
+	if t.opts.growLoad == 0 || t.Bits() >= t.opts.maxBits || t.iterating > 0 {
+		return
+	}
+
+	if t.LoadFactor() > t.opts.growLoad {
+		t.resize(t.Bits() + 1)
+	}
+}
+
+// shrink начинает уменьшение массива сегментов,
+// если коэффициент заполнения опустился ниже порога уменьшения.
+func (t *Table[K, V]) shrink() {
+	if t.opts.shrinkLoad == 0 || t.Bits() <= t.opts.bits || t.iterating > 0 {
+		return
+	}
+
+	if t.LoadFactor() < t.opts.shrinkLoad {
+		t.resize(t.Bits() - 1)
+	}
+}
+
+// resize заменяет массив сегментов массивом из 2^bits сегментов.
+// Элементы переносятся постепенно при последующих изменениях таблицы.
+func (t *Table[K, V]) resize(bits int) {
+	// Если предыдущий перенос не завершен, то завершаю его
+	for t.old != nil {
+		t.rehash(len(t.old))
+	}
+
+	t.old = t.buckets
+	t.oldMask = t.mask
+	t.evacuated = 0
+
+	t.buckets = make([]*Segment[K, V], 1<<bits)
+	t.mask = 1<<bits - 1
+}
+
+// rehash переносит элементы не более чем из n сегментов old
+// в текущий массив сегментов.
+// Во время итерации перенос приостанавливается,
+// так как он меняет порядок элементов.
+func (t *Table[K, V]) rehash(n int) {
+	if t.iterating > 0 {
+		return
+	}
+
+	for ; n > 0 && t.old != nil; n-- {
+		s := t.old[t.evacuated]
+		t.old[t.evacuated] = nil
+
+		// Сегмент отмечается перенесенным до переноса,
+		// чтобы push добавлял его элементы в текущий массив
+		t.evacuated++
+
+		if s != nil {
+			t.unlink(s)
+
+			for e := s.list; e != nil; {
+				next := e.next
+				t.push(e)
+				e = next
+			}
+
+			s.list = nil
+		}
+
+		if t.evacuated == len(t.old) {
+			t.old = nil
+			t.oldMask = 0
+			t.evacuated = 0
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package hashtable
+
+import "reflect"
+
+// Операции над таблицами как над множествами ключей.
+//
+// Если таблицы используют одну хэш функцию и одинаковое количество
+// сегментов и не переносят элементы, то сегменты с одинаковым ключом
+// содержат одни и те же ключи, поэтому таблицы сравниваются сегмент
+// за сегментом: ключ ищется только в списке соответствующего сегмента,
+// а если сегмента нет, то все элементы сегмента сразу считаются
+// отсутствующими. Иначе каждый ключ ищется в другой таблице отдельно.
+//
+// Результат операции — новая таблица с хэш функцией и настройками
+// первой таблицы, кроме ограничения WithCapacity и функций WithEvict
+// и WithExpire. Значения берутся из таблицы, в которой найден ключ,
+// а для общих ключей — из первой таблицы. Истекшие элементы не учитываются.
+//
+// Ключ ищется в другой таблице после ее нормализации, а в результат
+// добавляется после нормализации первой таблицы. Если у разных ключей
+// одна нормальная форма, то в результат попадает первый из них.
+
+// Union возвращает таблицу с ключами, которые есть в a или в b.
+func Union[K comparable, V any](a, b *Table[K, V]) *Table[K, V] {
+	res := a.like()
+	same := sameHasher(a.hash, b.hash)
+
+	compare(a, b, func(e *Element[K, V], _ bool) bool {
+		res.copy(e, true)
+		return true
+	})
+
+	compare(b, a, func(e *Element[K, V], found bool) bool {
+		if !found {
+			res.copy(e, same)
+		}
+
+		return true
+	})
+
+	return res
+}
+
+// Intersect возвращает таблицу с ключами, которые есть и в a, и в b.
+func Intersect[K comparable, V any](a, b *Table[K, V]) *Table[K, V] {
+	res := a.like()
+
+	compare(a, b, func(e *Element[K, V], found bool) bool {
+		if found {
+			res.copy(e, true)
+		}
+
+		return true
+	})
+
+	return res
+}
+
+// Difference возвращает таблицу с ключами a, которых нет в b.
+func Difference[K comparable, V any](a, b *Table[K, V]) *Table[K, V] {
+	res := a.like()
+
+	compare(a, b, func(e *Element[K, V], found bool) bool {
+		if !found {
+			res.copy(e, true)
+		}
+
+		return true
+	})
+
+	return res
+}
+
+// SymmetricDifference возвращает таблицу с ключами,
+// которые есть только в одной из таблиц a и b.
+func SymmetricDifference[K comparable, V any](a, b *Table[K, V]) *Table[K, V] {
+	res := a.like()
+	same := sameHasher(a.hash, b.hash)
+
+	compare(a, b, func(e *Element[K, V], found bool) bool {
+		if !found {
+			res.copy(e, true)
+		}
+
+		return true
+	})
+
+	compare(b, a, func(e *Element[K, V], found bool) bool {
+		if !found {
+			res.copy(e, same)
+		}
+
+		return true
+	})
+
+	return res
+}
+
+// IsSubset возвращает true, если все ключи a есть в b.
+func IsSubset[K comparable, V any](a, b *Table[K, V]) bool {
+	subset := true
+
+	compare(a, b, func(_ *Element[K, V], found bool) bool {
+		subset = found
+		return found
+	})
+
+	return subset
+}
+
+// Equal возвращает true, если таблицы a и b содержат одни и те же ключи.
+// Значения не сравниваются.
+func Equal[K comparable, V any](a, b *Table[K, V]) bool {
+	return IsSubset(a, b) && IsSubset(b, a)
+}
+
+// compare вызывает fn для каждого неистекшего элемента a
+// вместе с признаком того, что ключ элемента есть в b,
+// пока fn возвращает true.
+func compare[K comparable, V any](a, b *Table[K, V], fn func(e *Element[K, V], found bool) bool) {
+	same := sameHasher(a.hash, b.hash)
+
+	// Сегменты таблиц соответствуют друг другу,
+	// только если все элементы разложены одинаково
+	aligned := same && a.mask == b.mask && a.old == nil && b.old == nil
+
+	nowA := a.opts.clock.Now().UnixNano()
+	nowB := b.opts.clock.Now().UnixNano()
+
+	// Прохожусь по всем сегментам a
+	for s := a.head; s != nil; s = s.next {
+		var other *Element[K, V]
+		if aligned {
+			if seg := b.buckets[s.key]; seg != nil {
+				other = seg.list
+			}
+		}
+
+		// Прохожусь по элементам списка сегмента
+		for e := s.list; e != nil; e = e.next {
+			if e.expiredAt(nowA) {
+				continue
+			}
+
+			// Хэш значение из a подходит для b, только если
+			// нормализация b не изменила ключ
+			key := b.normalize(e.key)
+			var found *Element[K, V]
+
+			switch {
+			case aligned && key == e.key:
+				// Ищу только в соответствующем сегменте b
+				found = other.find(e.hash, key)
+			case same && key == e.key:
+				buckets, i := b.locate(e.hash)
+				if seg := buckets[i]; seg != nil {
+					found = seg.list.find(e.hash, key)
+				}
+			default:
+				_, _, found = b.lookup(key)
+			}
+
+			if !fn(e, found != nil && !found.expiredAt(nowB)) {
+				return
+			}
+		}
+	}
+}
+
+// like возвращает пустую таблицу с хэш функцией, настройками
+// и количеством сегментов таблицы t.
+// Ограничение количества элементов и функции удаления не переносятся,
+// чтобы результат операции не терял ключи и не вызывал функции таблицы t.
+func (t *Table[K, V]) like() *Table[K, V] {
+	opts := t.opts
+	opts.capacity = 0
+	opts.evict = nil
+	opts.expire = nil
+
+	return &Table[K, V]{
+		buckets: make([]*Segment[K, V], len(t.buckets)),
+		mask:    t.mask,
+		hash:    t.hash,
+		norm:    t.norm,
+		opts:    opts,
+	}
+}
+
+// copy добавляет в таблицу копию элемента с нормализованным ключом.
+// Если элемент взят из таблицы с другой хэш функцией или нормализация
+// изменила ключ, то хэш значение вычисляется заново.
+// Если ключ с той же нормальной формой уже есть, то таблица не изменяется.
+func (t *Table[K, V]) copy(e *Element[K, V], same bool) {
+	key, h := e.key, e.hash
+
+	if t.norm != nil {
+		if key = t.norm(key); key != e.key {
+			same = false
+		}
+	}
+
+	if !same {
+		h = t.hash.Hash(key)
+	}
+
+	// Разные ключи другой таблицы могут иметь одну нормальную форму
+	if t.norm != nil {
+		if _, _, dup := t.lookup(key); dup != nil {
+			return
+		}
+	}
+
+	t.add(&Element[K, V]{key: key, value: e.value, hash: h, expires: e.expires})
+}
+
+// sameHasher возвращает true, если хэш функции совпадают.
+// Хэш функции, значения которых нельзя сравнить, считаются разными.
+func sameHasher[K comparable](a, b Hasher[K]) bool {
+	ta := reflect.TypeOf(a)
+	return ta == reflect.TypeOf(b) && ta.Comparable() && any(a) == any(b)
+}
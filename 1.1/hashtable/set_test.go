@@ -0,0 +1,115 @@
+package hashtable
+
+import (
+	"maps"
+	"slices"
+	"testing"
+)
+
+// keys возвращает отсортированные ключи таблицы.
+func keys(t *Table[string, int]) []string {
+	return slices.Sorted(maps.Keys(maps.Collect(t.All())))
+}
+
+func TestSetDropsCapacity(t *testing.T) {
+	evicted := 0
+	onEvict := WithEvict(func(string, int) { evicted++ })
+
+	a := NewLRU(FNV1a{}, 2, onEvict)
+	a.Put("a", 1)
+	a.Put("b", 2)
+
+	b := NewLRU(FNV1a{}, 2, onEvict)
+	b.Put("c", 3)
+	b.Put("d", 4)
+
+	res := Union(a, b)
+
+	if got := keys(res); !slices.Equal(got, []string{"a", "b", "c", "d"}) {
+		t.Errorf("Union = %v, want [a b c d]", got)
+	}
+
+	if res.Capacity() != 0 || evicted != 0 {
+		t.Errorf("Capacity() = %d, вытеснено %d, want 0, 0", res.Capacity(), evicted)
+	}
+}
+
+func TestSetNormalizer(t *testing.T) {
+	fold := WithNormalizer[string, int](FoldCase)
+
+	tests := []struct {
+		name   string
+		a, b   []string
+		normA  bool
+		normB  bool
+		op     func(a, b *Table[string, int]) *Table[string, int]
+		want   []string
+		subset bool // IsSubset(a, b)
+	}{
+		{
+			name:  "ключи b нормализуются в результате",
+			a:     []string{"ab"},
+			b:     []string{"Ab", "XY", "xy"},
+			normA: true,
+			op:    Union[string, int],
+			want:  []string{"ab", "xy"},
+		},
+		{
+			name:  "разность с нормализацией a",
+			a:     []string{"ab", "cd"},
+			b:     []string{"AB"},
+			normA: true,
+			op:    Difference[string, int],
+			want:  []string{"ab", "cd"},
+		},
+		{
+			name:   "ключ a ищется после нормализации b",
+			a:      []string{"AB", "Cd"},
+			b:      []string{"ab", "cd", "ef"},
+			normB:  true,
+			op:     Intersect[string, int],
+			want:   []string{"AB", "Cd"},
+			subset: true,
+		},
+		{
+			name:   "обе таблицы с нормализацией",
+			a:      []string{"AB"},
+			b:      []string{"ab", "CD"},
+			normA:  true,
+			normB:  true,
+			op:     SymmetricDifference[string, int],
+			want:   []string{"cd"},
+			subset: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			build := func(list []string, norm bool) *Table[string, int] {
+				var opts []Option[string, int]
+				if norm {
+					opts = append(opts, fold)
+				}
+
+				// Одинаковое количество сегментов включает
+				// сравнение сегмент за сегментом
+				tbl := NewTable(FNV1a{}, append(opts, WithBits[string, int](4))...)
+				for i, k := range list {
+					tbl.Put(k, i)
+				}
+
+				return tbl
+			}
+
+			a, b := build(tt.a, tt.normA), build(tt.b, tt.normB)
+
+			if got := keys(tt.op(a, b)); !slices.Equal(got, tt.want) {
+				t.Errorf("результат %v, want %v", got, tt.want)
+			}
+
+			if got := IsSubset(a, b); got != tt.subset {
+				t.Errorf("IsSubset() = %v, want %v", got, tt.subset)
+			}
+		})
+	}
+}
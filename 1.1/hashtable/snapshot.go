@@ -0,0 +1,538 @@
+package hashtable
+
+import (
+	"bufio"
+	"encoding"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+	"math"
+	"math/bits"
+)
+
+// Формат снимка таблицы:
+//
+//	magic    [4]byte  "HTBL"
+//	version  uint8
+//	bits     uint8    разрядность ключа сегмента
+//	elements uvarint  количество элементов
+//	segments uvarint  количество сегментов
+//	сегменты в порядке списка сегментов:
+//	    key   uvarint  ключ сегмента
+//	    count uvarint  количество элементов
+//	    элементы в порядке списка: uvarint длина и байты ключа,
+//	                               uvarint длина и байты значения
+//	checksum uint32   CRC-32 (Castagnoli) всех предыдущих байт, big endian
+const (
+	snapshotMagic   = "HTBL"
+	snapshotVersion = 1
+)
+
+// maxRecordSize ограничивает длину ключа или значения в снимке,
+// чтобы поврежденный снимок не приводил к огромным выделениям памяти.
+const maxRecordSize = 1 << 26
+
+// Ошибки чтения и записи снимка таблицы.
+var (
+	ErrFormat          = errors.New("hashtable: неверный формат снимка")
+	ErrVersion         = errors.New("hashtable: неподдерживаемая версия снимка")
+	ErrChecksum        = errors.New("hashtable: неверная контрольная сумма снимка")
+	ErrHashMismatch    = errors.New("hashtable: хэш функция не совпадает с хэш функцией снимка")
+	ErrUnsupportedType = errors.New("hashtable: тип не поддерживает запись в снимок")
+	ErrRehashing       = errors.New("hashtable: таблица переносит элементы во время итерации")
+	ErrIterating       = errors.New("hashtable: снимок нельзя прочитать во время итерации")
+)
+
+var castagnoli = crc32.MakeTable(crc32.Castagnoli)
+
+// WriteTo записывает снимок таблицы, сохраняющий ключи сегментов
+// и порядок элементов.
+// Ключи и значения должны быть строками, срезами байт, числами,
+// логическими значениями, пустыми структурами или реализовывать
+// encoding.BinaryMarshaler.
+// Если таблица переносит элементы, то перенос завершается перед записью.
+func (t *Table[K, V]) WriteTo(w io.Writer) (int64, error) {
+	if t.old != nil {
+		if t.iterating > 0 {
+			return 0, ErrRehashing
+		}
+
+		t.rehash(len(t.old))
+	}
+
+	segments := 0
+	for s := t.head; s != nil; s = s.next {
+		segments++
+	}
+
+	sw := &snapshotWriter{w: bufio.NewWriter(w), crc: crc32.New(castagnoli)}
+
+	buf := append([]byte(snapshotMagic), snapshotVersion, uint8(t.Bits()))
+	buf = binary.AppendUvarint(buf, uint64(t.len))
+	buf = binary.AppendUvarint(buf, uint64(segments))
+	sw.write(buf)
+
+	for s := t.head; s != nil; s = s.next {
+		count := 0
+		for e := s.list; e != nil; e = e.next {
+			count++
+		}
+
+		buf = binary.AppendUvarint(buf[:0], s.key)
+		buf = binary.AppendUvarint(buf, uint64(count))
+
+		for e := s.list; e != nil; e = e.next {
+			var err error
+
+			if buf, err = appendRecord(buf, e.key); err != nil {
+				return sw.n, err
+			}
+
+			if buf, err = appendRecord(buf, e.value); err != nil {
+				return sw.n, err
+			}
+		}
+
+		sw.write(buf)
+	}
+
+	sw.write(binary.BigEndian.AppendUint32(buf[:0], sw.crc.Sum32()))
+
+	if sw.err == nil {
+		sw.err = sw.w.Flush()
+	}
+
+	return sw.n, sw.err
+}
+
+// ReadFrom заменяет содержимое таблицы снимком, записанным WriteTo.
+// Хэш функция таблицы должна совпадать с хэш функцией,
+// с которой снимок был записан, иначе возвращается ErrHashMismatch.
+// Если снимок поврежден, то таблица не изменяется.
+// Снимок не хранит порядок обхода, поэтому упорядоченная таблица
+// получает элементы в порядке снимка, а лишние по WithCapacity удаляются.
+// Срок жизни снимок тоже не хранит: элементы получают срок жизни WithTTL.
+// Снимок, в котором сегментов намного больше, чем элементов
+// (разрядность больше WithBits таблицы и bits.Len(elements)+1),
+// считается поврежденным, например снимок таблицы без уменьшения.
+// Во время итерации по таблице снимок не читается и возвращается ErrIterating.
+// Если r не является *bufio.Reader, то из него может быть
+// прочитано больше байт, чем занимает снимок.
+func (t *Table[K, V]) ReadFrom(r io.Reader) (int64, error) {
+	if t.iterating > 0 {
+		return 0, ErrIterating
+	}
+
+	br, ok := r.(*bufio.Reader)
+	if !ok {
+		br = bufio.NewReader(r)
+	}
+
+	sr := &snapshotReader{r: br, crc: crc32.New(castagnoli)}
+
+	err := t.readSnapshot(sr)
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		err = fmt.Errorf("%w: %w", ErrFormat, io.ErrUnexpectedEOF)
+	}
+
+	return sr.n, err
+}
+
+// readSnapshot читает снимок и, если он корректен, заменяет им содержимое таблицы.
+func (t *Table[K, V]) readSnapshot(sr *snapshotReader) error {
+	header := make([]byte, len(snapshotMagic)+2)
+	if err := sr.readFull(header); err != nil {
+		return err
+	}
+
+	if string(header[:len(snapshotMagic)]) != snapshotMagic {
+		return ErrFormat
+	}
+
+	if header[len(snapshotMagic)] != snapshotVersion {
+		return ErrVersion
+	}
+
+	size := int(header[len(snapshotMagic)+1])
+	if size > t.opts.maxBits {
+		return ErrHashMismatch
+	}
+
+	elements, err := sr.readUvarint()
+	if err != nil {
+		return err
+	}
+
+	// Разрядность ограничиваю количеством элементов, чтобы даже снимок
+	// с верной контрольной суммой не требовал огромного массива сегментов
+	if size > max(t.opts.bits, bits.Len64(elements)+1) {
+		return ErrFormat
+	}
+
+	segments, err := sr.readUvarint()
+	if err != nil {
+		return err
+	}
+
+	if segments > elements || segments > 1<<size {
+		return ErrFormat
+	}
+
+	// Массив сегментов выделяю только после проверки контрольной суммы,
+	// чтобы поврежденная разрядность не приводила к огромному выделению памяти
+	mask := uint64(1)<<size - 1
+	seen := make(map[uint64]bool)
+
+	var head, tail *Segment[K, V]
+	total := uint64(0)
+	mismatch := false
+
+	for range segments {
+		key, err := sr.readUvarint()
+		if err != nil {
+			return err
+		}
+
+		count, err := sr.readUvarint()
+		if err != nil {
+			return err
+		}
+
+		if key > mask || seen[key] || count == 0 || count > elements-total {
+			return ErrFormat
+		}
+
+		seen[key] = true
+		total += count
+
+		s := &Segment[K, V]{key: key, prev: tail}
+		var last *Element[K, V]
+
+		for range count {
+			e := &Element[K, V]{}
+
+			if err := sr.readRecord(&e.key); err != nil {
+				return err
+			}
+
+			if err := sr.readRecord(&e.value); err != nil {
+				return err
+			}
+
+			// Несовпадение хэш функции сообщаю после проверки
+			// контрольной суммы, чтобы отличить его от повреждения снимка
+			e.hash = t.hash.Hash(e.key)
+			if e.hash&mask != key {
+				mismatch = true
+			}
+
+			// Добавляю элемент в конец списка, сохраняя порядок
+			if last == nil {
+				s.list = e
+			} else {
+				last.next = e
+			}
+
+			last = e
+		}
+
+		// Добавляю сегмент в конец списка сегментов
+		if tail == nil {
+			head = s
+		} else {
+			tail.next = s
+		}
+
+		tail = s
+	}
+
+	if total != elements {
+		return ErrFormat
+	}
+
+	sum := sr.crc.Sum32()
+
+	checksum := make([]byte, 4)
+	if err := sr.readFull(checksum); err != nil {
+		return err
+	}
+
+	if binary.BigEndian.Uint32(checksum) != sum {
+		return ErrChecksum
+	}
+
+	if mismatch {
+		return ErrHashMismatch
+	}
+
+	buckets := make([]*Segment[K, V], 1<<size)
+	for s := head; s != nil; s = s.next {
+		buckets[s.key] = s
+	}
+
+	t.buckets = buckets
+	t.mask = mask
+	t.head = head
+	t.len = int(elements)
+	t.old = nil
+	t.oldMask = 0
+	t.evacuated = 0
+
+	t.oldest = nil
+	t.newest = nil
+
+	for s := head; s != nil; s = s.next {
+		for e := s.list; e != nil; e = e.next {
+			e.expires = t.deadline(t.opts.ttl)
+			t.enqueue(e)
+		}
+	}
+
+	t.evictExcess()
+
+	return nil
+}
+
+// snapshotWriter записывает снимок, считая байты и контрольную сумму.
+// После первой ошибки запись прекращается.
+type snapshotWriter struct {
+	w   *bufio.Writer
+	crc hash.Hash32
+	n   int64
+	err error
+}
+
+// write записывает байты снимка.
+func (sw *snapshotWriter) write(p []byte) {
+	if sw.err != nil {
+		return
+	}
+
+	n, err := sw.w.Write(p)
+	sw.n += int64(n)
+	sw.err = err
+
+	sw.crc.Write(p[:n])
+}
+
+// snapshotReader читает снимок, считая байты и контрольную сумму.
+type snapshotReader struct {
+	r   *bufio.Reader
+	crc hash.Hash32
+	n   int64
+}
+
+// readFull читает len(p) байт снимка.
+func (sr *snapshotReader) readFull(p []byte) error {
+	n, err := io.ReadFull(sr.r, p)
+	sr.n += int64(n)
+	sr.crc.Write(p[:n])
+
+	return err
+}
+
+// ReadByte читает один байт снимка.
+func (sr *snapshotReader) ReadByte() (byte, error) {
+	b, err := sr.r.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+
+	sr.n++
+	sr.crc.Write([]byte{b})
+
+	return b, nil
+}
+
+// readUvarint читает число в формате uvarint.
+func (sr *snapshotReader) readUvarint() (uint64, error) {
+	return binary.ReadUvarint(sr)
+}
+
+// readRecord читает запись с длиной и декодирует ее в v.
+func (sr *snapshotReader) readRecord(v any) error {
+	size, err := sr.readUvarint()
+	if err != nil {
+		return err
+	}
+
+	if size > maxRecordSize {
+		return ErrFormat
+	}
+
+	data := make([]byte, size)
+	if err := sr.readFull(data); err != nil {
+		return err
+	}
+
+	return decodeRecord(data, v)
+}
+
+// appendRecord добавляет к buf длину и байты значения v.
+func appendRecord(buf []byte, v any) ([]byte, error) {
+	data, err := encodeRecord(v)
+	if err != nil {
+		return buf, err
+	}
+
+	buf = binary.AppendUvarint(buf, uint64(len(data)))
+
+	return append(buf, data...), nil
+}
+
+// encodeRecord возвращает байты значения v.
+func encodeRecord(v any) ([]byte, error) {
+	switch v := v.(type) {
+	case string:
+		return []byte(v), nil
+	case []byte:
+		return v, nil
+	case struct{}:
+		return nil, nil
+	case bool:
+		if v {
+			return []byte{1}, nil
+		}
+
+		return []byte{0}, nil
+	case int:
+		return binary.AppendVarint(nil, int64(v)), nil
+	case int8:
+		return binary.AppendVarint(nil, int64(v)), nil
+	case int16:
+		return binary.AppendVarint(nil, int64(v)), nil
+	case int32:
+		return binary.AppendVarint(nil, int64(v)), nil
+	case int64:
+		return binary.AppendVarint(nil, v), nil
+	case uint:
+		return binary.AppendUvarint(nil, uint64(v)), nil
+	case uint8:
+		return binary.AppendUvarint(nil, uint64(v)), nil
+	case uint16:
+		return binary.AppendUvarint(nil, uint64(v)), nil
+	case uint32:
+		return binary.AppendUvarint(nil, uint64(v)), nil
+	case uint64:
+		return binary.AppendUvarint(nil, v), nil
+	case float32:
+		return binary.BigEndian.AppendUint32(nil, math.Float32bits(v)), nil
+	case float64:
+		return binary.BigEndian.AppendUint64(nil, math.Float64bits(v)), nil
+	case encoding.BinaryMarshaler:
+		return v.MarshalBinary()
+	}
+
+	return nil, fmt.Errorf("%w: %T", ErrUnsupportedType, v)
+}
+
+// decodeRecord декодирует байты data в значение, на которое указывает v.
+func decodeRecord(data []byte, v any) error {
+	switch v := v.(type) {
+	case *string:
+		*v = string(data)
+		return nil
+	case *[]byte:
+		*v = data
+		return nil
+	case *struct{}:
+		return checkSize(data, 0)
+	case *bool:
+		if err := checkSize(data, 1); err != nil {
+			return err
+		}
+
+		*v = data[0] != 0
+		return nil
+	case *int:
+		x, err := decodeVarint(data)
+		*v = int(x)
+		return err
+	case *int8:
+		x, err := decodeVarint(data)
+		*v = int8(x)
+		return err
+	case *int16:
+		x, err := decodeVarint(data)
+		*v = int16(x)
+		return err
+	case *int32:
+		x, err := decodeVarint(data)
+		*v = int32(x)
+		return err
+	case *int64:
+		x, err := decodeVarint(data)
+		*v = x
+		return err
+	case *uint:
+		x, err := decodeUvarint(data)
+		*v = uint(x)
+		return err
+	case *uint8:
+		x, err := decodeUvarint(data)
+		*v = uint8(x)
+		return err
+	case *uint16:
+		x, err := decodeUvarint(data)
+		*v = uint16(x)
+		return err
+	case *uint32:
+		x, err := decodeUvarint(data)
+		*v = uint32(x)
+		return err
+	case *uint64:
+		x, err := decodeUvarint(data)
+		*v = x
+		return err
+	case *float32:
+		if err := checkSize(data, 4); err != nil {
+			return err
+		}
+
+		*v = math.Float32frombits(binary.BigEndian.Uint32(data))
+		return nil
+	case *float64:
+		if err := checkSize(data, 8); err != nil {
+			return err
+		}
+
+		*v = math.Float64frombits(binary.BigEndian.Uint64(data))
+		return nil
+	case encoding.BinaryUnmarshaler:
+		return v.UnmarshalBinary(data)
+	}
+
+	return fmt.Errorf("%w: %T", ErrUnsupportedType, v)
+}
+
+// checkSize проверяет, что запись имеет указанную длину.
+func checkSize(data []byte, size int) error {
+	if len(data) != size {
+		return ErrFormat
+	}
+
+	return nil
+}
+
+// decodeVarint декодирует запись, содержащую одно число в формате varint.
+func decodeVarint(data []byte) (int64, error) {
+	x, n := binary.Varint(data)
+	if n <= 0 || n != len(data) {
+		return 0, ErrFormat
+	}
+
+	return x, nil
+}
+
+// decodeUvarint декодирует запись, содержащую одно число в формате uvarint.
+func decodeUvarint(data []byte) (uint64, error) {
+	x, n := binary.Uvarint(data)
+	if n <= 0 || n != len(data) {
+		return 0, ErrFormat
+	}
+
+	return x, nil
+}
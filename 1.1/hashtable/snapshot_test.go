@@ -0,0 +1,126 @@
+package hashtable
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"strconv"
+	"testing"
+)
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	tbl := NewTable[string, int](FNV1a{}, WithBits[string, int](2))
+	want := make(map[string]int)
+
+	for i := range 1000 {
+		key := strconv.Itoa(i)
+		tbl.Put(key, i)
+		want[key] = i
+	}
+
+	var buf bytes.Buffer
+	if _, err := tbl.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo: %v", err)
+	}
+
+	got := NewTable[string, int](FNV1a{})
+	if _, err := got.ReadFrom(&buf); err != nil {
+		t.Fatalf("ReadFrom: %v", err)
+	}
+
+	if got.Bits() != tbl.Bits() {
+		t.Errorf("Bits() = %d, want %d", got.Bits(), tbl.Bits())
+	}
+
+	checkTable(t, got, want)
+}
+
+// signed добавляет к снимку его контрольную сумму.
+func signed(data string) []byte {
+	return binary.BigEndian.AppendUint32([]byte(data), crc32.Checksum([]byte(data), castagnoli))
+}
+
+func TestSnapshotCorrupt(t *testing.T) {
+	var valid bytes.Buffer
+
+	tbl := NewTable[string, int](FNV1a{})
+	tbl.Put("a", 1)
+	tbl.Put("b", 2)
+
+	if _, err := tbl.WriteTo(&valid); err != nil {
+		t.Fatalf("WriteTo: %v", err)
+	}
+
+	flipped := bytes.Clone(valid.Bytes())
+	flipped[len(flipped)-5] ^= 1
+
+	tests := []struct {
+		name string
+		data []byte
+		err  error
+	}{
+		{"пустой", nil, ErrFormat},
+		{"магия", []byte("HTBX\x01\x08\x00\x00"), ErrFormat},
+		{"версия", []byte("HTBL\x02\x08\x00\x00"), ErrVersion},
+		// Огромная разрядность без элементов не должна выделять массив сегментов
+		{"разрядность", []byte("HTBL\x01\x1e\x00\x00"), ErrFormat},
+		// Контрольная сумма верна, но 2^32 сегментов для пустой таблицы
+		// означают поврежденный или намеренно составленный снимок
+		{"разрядность с контрольной суммой", signed("HTBL\x01\x20\x00\x00"), ErrFormat},
+		{"разрядность больше элементов", signed("HTBL\x01\x10\x03\x00"), ErrFormat},
+		{"разрядность больше хэш функции", signed("HTBL\x01\x41\x00\x00"), ErrHashMismatch},
+		{"сегментов больше элементов", []byte("HTBL\x01\x08\x01\x02"), ErrFormat},
+		{"обрезан", valid.Bytes()[:valid.Len()-1], ErrFormat},
+		{"контрольная сумма", flipped, ErrChecksum},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewTable[string, int](FNV1a{})
+			got.Put("x", 0)
+
+			if _, err := got.ReadFrom(bytes.NewReader(tt.data)); !errors.Is(err, tt.err) {
+				t.Fatalf("ReadFrom() = %v, want %v", err, tt.err)
+			}
+
+			// Поврежденный снимок не изменяет таблицу
+			if v, ok := got.Get("x"); !ok || v != 0 || got.Len() != 1 {
+				t.Errorf("таблица изменилась: Len() = %d, Get(x) = %d, %v", got.Len(), v, ok)
+			}
+		})
+	}
+}
+
+func TestSnapshotDuringIteration(t *testing.T) {
+	src := NewTable[string, int](FNV1a{})
+	src.Put("a", 1)
+
+	var buf bytes.Buffer
+	if _, err := src.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo: %v", err)
+	}
+
+	tbl := NewTable[string, int](FNV1a{})
+	tbl.Put("x", 0)
+	tbl.Put("y", 0)
+
+	n := 0
+	for range tbl.All() {
+		if _, err := tbl.ReadFrom(bytes.NewReader(buf.Bytes())); !errors.Is(err, ErrIterating) {
+			t.Fatalf("ReadFrom() во время итерации = %v, want %v", err, ErrIterating)
+		}
+
+		n++
+	}
+
+	if n != 2 || tbl.Len() != 2 {
+		t.Errorf("выдано %d элементов, Len() = %d, want 2, 2", n, tbl.Len())
+	}
+
+	if _, err := tbl.ReadFrom(&buf); err != nil {
+		t.Fatalf("ReadFrom() после итерации: %v", err)
+	}
+
+	checkTable(t, tbl, map[string]int{"a": 1})
+}
@@ -0,0 +1,70 @@
+package hashtable
+
+// Stats содержит сведения о распределении элементов по сегментам.
+type Stats struct {
+	Buckets      int     `json:"buckets"`       // Количество сегментов в массиве
+	Segments     int     `json:"segments"`      // Количество непустых сегментов
+	Elements     int     `json:"elements"`      // Количество элементов
+	LongestChain int     `json:"longest_chain"` // Длина самого длинного списка элементов
+	LoadFactor   float64 `json:"load_factor"`   // Среднее количество элементов на сегмент массива
+
+	// Histogram[n] содержит количество сегментов массива с n элементами,
+	// включая пустые сегменты в Histogram[0].
+	Histogram []int `json:"histogram"`
+
+	// ChiSquared содержит значение критерия хи-квадрат для гипотезы
+	// о равномерном распределении элементов по сегментам.
+	ChiSquared float64 `json:"chi_squared"`
+
+	// Uniformity содержит отношение ChiSquared к числу степеней свободы.
+	// Для равномерного распределения значение близко к 1,
+	// чем оно больше, тем хуже хэш функция распределяет ключи.
+	Uniformity float64 `json:"uniformity"`
+}
+
+// Stats возвращает сведения о распределении элементов по сегментам.
+// Если таблица переносит элементы, то сведения относятся
+// к массиву сегментов, в который элементы переносятся.
+func (t *Table[K, V]) Stats() Stats {
+	counts := make([]int, len(t.buckets))
+
+	// Прохожусь по всем элементам и считаю их в сегментах текущего массива
+	for s := t.head; s != nil; s = s.next {
+		for e := s.list; e != nil; e = e.next {
+			counts[e.hash&t.mask]++
+		}
+	}
+
+	st := Stats{
+		Buckets:    len(t.buckets),
+		Elements:   t.len,
+		LoadFactor: t.LoadFactor(),
+	}
+
+	for _, n := range counts {
+		if n > 0 {
+			st.Segments++
+		}
+
+		st.LongestChain = max(st.LongestChain, n)
+	}
+
+	st.Histogram = make([]int, st.LongestChain+1)
+	for _, n := range counts {
+		st.Histogram[n]++
+	}
+
+	if st.Elements == 0 || st.Buckets < 2 {
+		return st
+	}
+
+	expected := st.LoadFactor
+	for _, n := range counts {
+		d := float64(n) - expected
+		st.ChiSquared += d * d / expected
+	}
+
+	st.Uniformity = st.ChiSquared / float64(st.Buckets-1)
+
+	return st
+}
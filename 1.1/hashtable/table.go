@@ -0,0 +1,453 @@
+// Package hashtable реализует хэш-таблицу в виде списка сегментов,
+// каждый из которых хранит список элементов с одинаковым значением хэш функции.
+//
+// Хэш значение ключа напрямую индексирует массив сегментов,
+// а непустые сегменты дополнительно связаны в список,
+// по которому таблица выводится на экран.
+// При росте и уменьшении количества элементов таблица постепенно
+// переносит элементы в массив сегментов другого размера.
+package hashtable
+
+import (
+	"fmt"
+	"io"
+	"math/bits"
+	"time"
+)
+
+// Table представляет хэш-таблицу.
+type Table[K comparable, V any] struct {
+	buckets  []*Segment[K, V] // Сегменты, индексированные ключом сегмента
+	mask     uint64           // Маска ключа сегмента
+	head     *Segment[K, V]   // Ссылка на первый непустой сегмент
+	hash     Hasher[K]        // Хэш функция ключа
+	norm     func(K) K        // Нормализация ключа или nil
+	evict    func(K, V)       // Функция удаления при переполнении или nil
+	onExpire func(K, V)       // Функция удаления истекших элементов или nil
+	len      int              // Количество элементов
+	opts     options[K, V]    // Настройки таблицы
+
+	old       []*Segment[K, V] // Сегменты, элементы которых еще не перенесены
+	oldMask   uint64           // Маска ключа сегмента в old
+	evacuated int              // Количество перенесенных сегментов old
+	iterating int              // Количество незавершенных итераций
+
+	oldest   *Element[K, V] // Первый элемент в порядке обхода
+	newest   *Element[K, V] // Последний элемент в порядке обхода
+	enqueued uint64         // Количество постановок элементов в порядок обхода
+}
+
+// Segment представляет звено списка сегментов
+type Segment[K comparable, V any] struct {
+	key  uint64
+	list *Element[K, V] // Ссылка на первый элемент списка
+	prev *Segment[K, V] // Ссылка на предыдущий сегмент
+	next *Segment[K, V] // Ссылка на следующий сегмент
+}
+
+// Element представляет звено списка элементов
+type Element[K comparable, V any] struct {
+	key     K
+	value   V
+	hash    uint64         // Хэш значение ключа
+	expires int64          // Время истечения в наносекундах, 0 если элемент не истекает
+	removed bool           // Элемент удален из таблицы
+	next    *Element[K, V] // Ссылка на следующий элемент
+	before  *Element[K, V] // Ссылка на предыдущий элемент в порядке обхода
+	after   *Element[K, V] // Ссылка на следующий элемент в порядке обхода
+	seq     uint64         // Номер элемента в порядке обхода
+}
+
+// NewTable возвращает пустую таблицу,
+// использующую переданную хэш функцию.
+func NewTable[K comparable, V any](hash Hasher[K], opts ...Option[K, V]) *Table[K, V] {
+	o := newOptions(hash.Bits(), opts)
+
+	return &Table[K, V]{
+		buckets:  make([]*Segment[K, V], 1<<o.bits),
+		mask:     1<<o.bits - 1,
+		hash:     hash,
+		norm:     o.normalize,
+		evict:    o.evict,
+		onExpire: o.expire,
+		opts:     o,
+	}
+}
+
+// Bits возвращает разрядность ключа сегмента.
+func (t *Table[K, V]) Bits() int {
+	return bits.TrailingZeros(uint(len(t.buckets)))
+}
+
+// Hasher возвращает хэш функцию таблицы.
+func (t *Table[K, V]) Hasher() Hasher[K] {
+	return t.hash
+}
+
+// Len возвращает количество элементов в таблице.
+func (t *Table[K, V]) Len() int {
+	return t.len
+}
+
+// LoadFactor возвращает среднее количество элементов на сегмент массива.
+func (t *Table[K, V]) LoadFactor() float64 {
+	return float64(t.len) / float64(len(t.buckets))
+}
+
+// Head возвращает первый сегмент таблицы.
+// Если таблица пуста, то возвращает nil.
+func (t *Table[K, V]) Head() *Segment[K, V] {
+	return t.head
+}
+
+// Print выводит список элементов таблицы.
+func (t *Table[K, V]) Print(w io.Writer) error {
+	i := 0
+
+	// Прохожусь по всем сегментам
+	for s := t.head; s != nil; s = s.next {
+		// Прохожусь по элементам списка сегмента
+		for e := s.list; e != nil; e = e.next {
+			if _, err := fmt.Fprintf(w, "%-3d\tKey: %-3d\tValue: %v\n", i, s.key, e.key); err != nil {
+				return err
+			}
+			i++
+		}
+	}
+
+	return nil
+}
+
+// PushForward добавляет элемент в начало списка сегмента.
+// Если элемент с таким ключом существует, то таблица не изменяется.
+// Возвращает ссылку на сегмент.
+func (t *Table[K, V]) PushForward(key K, value V) *Segment[K, V] {
+	key = t.normalize(key)
+	t.rehash(rehashStep)
+
+	h, s, e := t.search(key)
+	if e != nil {
+		return s
+	}
+
+	return t.insert(h, key, value, t.opts.ttl)
+}
+
+// Delete удаляет элемент из таблицы.
+// Возвращает значение удаленного элемента и true,
+// если элемент существовал.
+func (t *Table[K, V]) Delete(key K) (V, bool) {
+	key = t.normalize(key)
+	t.rehash(rehashStep)
+
+	e := t.remove(t.hash.Hash(key), key)
+	if e == nil {
+		var zero V
+		return zero, false
+	}
+
+	return e.value, true
+}
+
+// Put записывает значение по ключу.
+// Если элемента с таким ключом нет, то добавляет его.
+// Элемент получает срок жизни WithTTL.
+func (t *Table[K, V]) Put(key K, value V) {
+	t.PutTTL(key, value, t.opts.ttl)
+}
+
+// Get возвращает значение по ключу и true,
+// если элемент существует.
+func (t *Table[K, V]) Get(key K) (V, bool) {
+	if _, _, e := t.search(t.normalize(key)); e != nil {
+		t.touch(e)
+		return e.value, true
+	}
+
+	var zero V
+	return zero, false
+}
+
+// GetOrInsert возвращает значение существующего элемента и true.
+// Если элемента нет, то добавляет переданное значение
+// и возвращает его и false.
+func (t *Table[K, V]) GetOrInsert(key K, value V) (V, bool) {
+	key = t.normalize(key)
+	t.rehash(rehashStep)
+
+	h, _, e := t.search(key)
+	if e != nil {
+		t.touch(e)
+		return e.value, true
+	}
+
+	t.insert(h, key, value, t.opts.ttl)
+
+	return value, false
+}
+
+// Update заменяет значение по ключу результатом fn.
+// Если элемента нет, то fn получает нулевое значение,
+// а результат добавляется в таблицу.
+// Элемент получает срок жизни WithTTL.
+// Возвращает новое значение.
+func (t *Table[K, V]) Update(key K, fn func(old V) V) V {
+	key = t.normalize(key)
+	t.rehash(rehashStep)
+
+	h, _, e := t.search(key)
+	if e != nil {
+		e.value = fn(e.value)
+		e.expires = t.deadline(t.opts.ttl)
+		t.touch(e)
+
+		return e.value
+	}
+
+	var zero V
+	value := fn(zero)
+	t.insert(h, key, value, t.opts.ttl)
+
+	return value
+}
+
+// Find возвращает ссылку на элемент.
+// Если элемент не существует, то возвращает nil.
+// Истекший элемент удаляется из таблицы.
+func (t *Table[K, V]) Find(key K) *Element[K, V] {
+	_, _, e := t.search(t.normalize(key))
+	if e != nil {
+		t.touch(e)
+	}
+
+	return e
+}
+
+// normalize возвращает нормальную форму ключа,
+// если таблице задана нормализация.
+func (t *Table[K, V]) normalize(key K) K {
+	if t.norm == nil {
+		return key
+	}
+
+	return t.norm(key)
+}
+
+// lookup возвращает хэш значение ключа, соответствующий ему сегмент
+// и элемент с ключом.
+// Если сегмент или элемент не существует, то вместо него возвращает nil.
+func (t *Table[K, V]) lookup(key K) (uint64, *Segment[K, V], *Element[K, V]) {
+	h := t.hash.Hash(key)
+	buckets, i := t.locate(h)
+
+	s := buckets[i]
+	if s == nil {
+		return h, nil, nil
+	}
+
+	return h, s, s.list.find(h, key)
+}
+
+// locate возвращает массив сегментов и индекс сегмента в нем,
+// соответствующие хэш значению.
+// Пока элементы переносятся, сегменты, которые еще не перенесены,
+// ищутся в old.
+func (t *Table[K, V]) locate(h uint64) ([]*Segment[K, V], uint64) {
+	if t.old != nil {
+		if i := h & t.oldMask; i >= uint64(t.evacuated) {
+			return t.old, i
+		}
+	}
+
+	return t.buckets, h & t.mask
+}
+
+// insert добавляет новый элемент со сроком жизни ttl в начало списка сегмента.
+// Если сегмента нет, то создает его в начале списка сегментов.
+// Возвращает ссылку на сегмент.
+func (t *Table[K, V]) insert(h uint64, key K, value V, ttl time.Duration) *Segment[K, V] {
+	return t.add(&Element[K, V]{key: key, value: value, hash: h, expires: t.deadline(ttl)})
+}
+
+// add добавляет новый элемент, ключа которого нет в таблице.
+// Возвращает ссылку на сегмент.
+func (t *Table[K, V]) add(e *Element[K, V]) *Segment[K, V] {
+	s := t.push(e)
+	t.len++
+	t.enqueue(e)
+
+	t.grow()
+	t.evictExcess()
+
+	return s
+}
+
+// remove удаляет элемент с ключом и его хэш значением.
+// Возвращает удаленный элемент или nil.
+func (t *Table[K, V]) remove(h uint64, key K) *Element[K, V] {
+	buckets, i := t.locate(h)
+
+	s := buckets[i]
+	if s == nil {
+		return nil
+	}
+
+	e := s.delete(h, key)
+	if e == nil {
+		return nil
+	}
+
+	t.len--
+	t.dequeue(e)
+
+	// Пустой сегмент удаляю из таблицы
+	if s.list == nil {
+		buckets[i] = nil
+		t.unlink(s)
+	}
+
+	t.shrink()
+
+	return e
+}
+
+// push добавляет элемент в начало списка соответствующего сегмента.
+// Если сегмента нет, то создает его в начале списка сегментов.
+// Возвращает ссылку на сегмент.
+func (t *Table[K, V]) push(e *Element[K, V]) *Segment[K, V] {
+	buckets, i := t.locate(e.hash)
+
+	s := buckets[i]
+	if s == nil {
+		s = &Segment[K, V]{key: i}
+		buckets[i] = s
+		t.link(s)
+	}
+
+	e.next = s.list
+	s.list = e
+
+	return s
+}
+
+// link добавляет сегмент в начало списка сегментов.
+func (t *Table[K, V]) link(s *Segment[K, V]) {
+	s.prev = nil
+	s.next = t.head
+
+	if t.head != nil {
+		t.head.prev = s
+	}
+
+	t.head = s
+}
+
+// unlink удаляет сегмент из списка сегментов.
+func (t *Table[K, V]) unlink(s *Segment[K, V]) {
+	if s.prev == nil {
+		t.head = s.next
+	} else {
+		s.prev.next = s.next
+	}
+
+	if s.next != nil {
+		s.next.prev = s.prev
+	}
+
+	// Ссылку на следующий сегмент сохраняю,
+	// чтобы итерация, стоящая на этом сегменте, могла продолжиться
+	s.prev = nil
+}
+
+// Key возвращает ключ сегмента.
+func (s *Segment[K, V]) Key() uint64 {
+	return s.key
+}
+
+// Next возвращает следующий сегмент или nil.
+// Для удаленного из таблицы сегмента возвращает nil.
+func (s *Segment[K, V]) Next() *Segment[K, V] {
+	if s.list == nil {
+		return nil
+	}
+
+	return s.next
+}
+
+// Front возвращает первый элемент списка сегмента.
+func (s *Segment[K, V]) Front() *Element[K, V] {
+	return s.list
+}
+
+// delete удаляет элемент из списка сегмента.
+// Возвращает удаленный элемент или nil.
+func (s *Segment[K, V]) delete(h uint64, key K) *Element[K, V] {
+	var prev *Element[K, V]
+
+	// Прохожусь по всем элементам списка сегмента
+	for e := s.list; e != nil; e = e.next {
+		// Если ключ элемента совпал с переданным, то удаляю
+		if e.hash == h && e.key == key {
+			if prev == nil {
+				s.list = e.next
+			} else {
+				prev.next = e.next
+			}
+
+			// Ссылку на следующий элемент сохраняю,
+			// чтобы итерация, стоящая на этом элементе, могла продолжиться
+			e.removed = true
+			return e
+		}
+
+		prev = e
+	}
+
+	return nil
+}
+
+// Key возвращает ключ элемента.
+func (e *Element[K, V]) Key() K {
+	return e.key
+}
+
+// Value возвращает значение элемента.
+func (e *Element[K, V]) Value() V {
+	return e.value
+}
+
+// Next возвращает следующий элемент списка или nil.
+// Для удаленного из таблицы элемента возвращает nil.
+func (e *Element[K, V]) Next() *Element[K, V] {
+	if e.removed {
+		return nil
+	}
+
+	return e.next
+}
+
+// Find возвращает ссылку на элемент списка, начиная с данного.
+// Если элемент не существует, то возвращает nil.
+func (e *Element[K, V]) Find(key K) *Element[K, V] {
+	// Прохожусь по всем элементам списка
+	for ; e != nil; e = e.next {
+		// Если совпал ключ элемента с переданным, то возвращаю его
+		if e.key == key {
+			return e
+		}
+	}
+
+	return nil
+}
+
+// find возвращает ссылку на элемент списка с ключом и его хэш значением.
+// Если элемент не существует, то возвращает nil.
+func (e *Element[K, V]) find(h uint64, key K) *Element[K, V] {
+	for ; e != nil; e = e.next {
+		if e.hash == h && e.key == key {
+			return e
+		}
+	}
+
+	return nil
+}
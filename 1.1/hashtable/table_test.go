@@ -0,0 +1,305 @@
+package hashtable
+
+import (
+	"math/rand/v2"
+	"strconv"
+	"testing"
+)
+
+// checkTable сравнивает таблицу с map и проверяет, что каждый элемент
+// лежит в сегменте своего массива: в old, если сегмент еще не перенесен,
+// и в buckets иначе.
+func checkTable(t *testing.T, tbl *Table[string, int], want map[string]int) {
+	t.Helper()
+
+	if tbl.Len() != len(want) {
+		t.Fatalf("Len() = %d, want %d", tbl.Len(), len(want))
+	}
+
+	for k, v := range want {
+		if got, ok := tbl.Get(k); !ok || got != v {
+			t.Fatalf("Get(%q) = %d, %v, want %d, true", k, got, ok, v)
+		}
+	}
+
+	seen := 0
+	for k, v := range tbl.All() {
+		if want[k] != v {
+			t.Fatalf("All() выдал %q = %d, want %d", k, v, want[k])
+		}
+
+		seen++
+	}
+
+	if seen != len(want) {
+		t.Fatalf("All() выдал %d элементов, want %d", seen, len(want))
+	}
+
+	segments, elements := 0, 0
+
+	check := func(buckets []*Segment[string, int], mask uint64, from int) {
+		for i := from; i < len(buckets); i++ {
+			s := buckets[i]
+			if s == nil {
+				continue
+			}
+
+			if s.key != uint64(i) || s.list == nil {
+				t.Fatalf("сегмент %d: ключ %d, пустой %v", i, s.key, s.list == nil)
+			}
+
+			for e := s.list; e != nil; e = e.next {
+				if e.hash&mask != uint64(i) {
+					t.Fatalf("элемент %q с хэш значением %x в сегменте %d", e.key, e.hash, i)
+				}
+
+				elements++
+			}
+
+			segments++
+		}
+	}
+
+	check(tbl.buckets, tbl.mask, 0)
+
+	if tbl.old != nil {
+		for i := range tbl.evacuated {
+			if tbl.old[i] != nil {
+				t.Fatalf("перенесенный сегмент %d old не пуст", i)
+			}
+		}
+
+		check(tbl.old, tbl.oldMask, tbl.evacuated)
+	}
+
+	if elements != len(want) {
+		t.Fatalf("в сегментах %d элементов, want %d", elements, len(want))
+	}
+
+	linked := 0
+	for s := tbl.head; s != nil; s = s.next {
+		if s.next != nil && s.next.prev != s {
+			t.Fatalf("нарушена ссылка prev сегмента %d", s.next.key)
+		}
+
+		linked++
+	}
+
+	if linked != segments {
+		t.Fatalf("в списке %d сегментов, в массивах %d", linked, segments)
+	}
+}
+
+func TestTableMatchesMap(t *testing.T) {
+	hashers := []struct {
+		name string
+		hash Hasher[string]
+	}{
+		{"pearson8", Pearson8},
+		{"pearson16", Pearson16},
+		{"fnv1a", FNV1a{}},
+	}
+
+	const ops = 200000
+
+	for _, h := range hashers {
+		t.Run(h.name, func(t *testing.T) {
+			rng := rand.New(rand.NewPCG(1, 2))
+			tbl := NewTable[string, int](h.hash, WithBits[string, int](2))
+			want := make(map[string]int)
+
+			rehashing, grew, shrank := false, false, false
+
+			for i := range ops {
+				// Чередую фазы роста и уменьшения, чтобы таблица
+				// несколько раз менялась в обе стороны
+				growing := i/40000%2 == 0
+				key := strconv.Itoa(rng.IntN(5000))
+				bits := tbl.Bits()
+
+				r := rng.IntN(10)
+				if !growing && r < 7 {
+					r = 9
+				}
+
+				switch {
+				case r < 4:
+					tbl.Put(key, i)
+					want[key] = i
+				case r < 5:
+					got := tbl.Update(key, func(old int) int { return old + 1 })
+					want[key]++
+
+					if got != want[key] {
+						t.Fatalf("Update(%q) = %d, want %d", key, got, want[key])
+					}
+				case r < 6:
+					got, ok := tbl.GetOrInsert(key, i)
+					old, exists := want[key]
+
+					if !exists {
+						want[key], old = i, i
+					}
+
+					if got != old || ok != exists {
+						t.Fatalf("GetOrInsert(%q) = %d, %v, want %d, %v", key, got, ok, old, exists)
+					}
+				case r < 8:
+					got, ok := tbl.Get(key)
+					old, exists := want[key]
+
+					if got != old || ok != exists {
+						t.Fatalf("Get(%q) = %d, %v, want %d, %v", key, got, ok, old, exists)
+					}
+				default:
+					got, ok := tbl.Delete(key)
+					old, exists := want[key]
+					delete(want, key)
+
+					if got != old || ok != exists {
+						t.Fatalf("Delete(%q) = %d, %v, want %d, %v", key, got, ok, old, exists)
+					}
+				}
+
+				rehashing = rehashing || tbl.Rehashing()
+				grew = grew || tbl.Bits() > bits
+				shrank = shrank || tbl.Bits() < bits
+
+				if i%5000 == 0 || tbl.Rehashing() && i%97 == 0 {
+					checkTable(t, tbl, want)
+				}
+			}
+
+			checkTable(t, tbl, want)
+
+			if !rehashing || !grew || !shrank {
+				t.Errorf("перенос %v, рост %v, уменьшение %v: проверка не затронула перенос", rehashing, grew, shrank)
+			}
+		})
+	}
+}
+
+func TestResizeFinishesPendingRehash(t *testing.T) {
+	// С малым коэффициентом роста таблица растет раньше,
+	// чем успевает перенести элементы предыдущего роста
+	tbl := NewTable[string, int](FNV1a{}, WithBits[string, int](1), WithGrowLoad[string, int](0.1))
+	want := make(map[string]int)
+	pendingResizes := 0
+
+	for i := range 5000 {
+		key := strconv.Itoa(i)
+		bits := tbl.Bits()
+		pending := tbl.Rehashing()
+
+		tbl.Put(key, i)
+		want[key] = i
+
+		if pending && tbl.Bits() != bits {
+			pendingResizes++
+			checkTable(t, tbl, want)
+		}
+	}
+
+	checkTable(t, tbl, want)
+
+	if pendingResizes == 0 {
+		t.Fatal("resize ни разу не застал незавершенный перенос")
+	}
+}
+
+func TestShrinkDuringRehash(t *testing.T) {
+	tbl := NewTable[string, int](FNV1a{}, WithBits[string, int](1))
+	want := make(map[string]int)
+
+	for i := range 4096 {
+		key := strconv.Itoa(i)
+		tbl.Put(key, i)
+		want[key] = i
+	}
+
+	grown := tbl.Bits()
+	pendingShrinks := 0
+
+	// Удаляю почти все элементы: каждое следующее уменьшение
+	// наступает раньше, чем завершается перенос предыдущего
+	for i := range 4090 {
+		key := strconv.Itoa(i)
+		bits := tbl.Bits()
+		pending := tbl.Rehashing()
+
+		tbl.Delete(key)
+		delete(want, key)
+
+		if pending && tbl.Bits() < bits {
+			pendingShrinks++
+		}
+
+		if tbl.Rehashing() && i%7 == 0 {
+			checkTable(t, tbl, want)
+		}
+	}
+
+	checkTable(t, tbl, want)
+
+	if tbl.Bits() >= grown || pendingShrinks == 0 {
+		t.Errorf("Bits() = %d после удаления из %d, уменьшений во время переноса %d", tbl.Bits(), grown, pendingShrinks)
+	}
+}
+
+// chainFind ищет элемент, проходя по списку сегментов,
+// как это делала таблица до появления массива сегментов.
+func chainFind(t *Table[string, struct{}], key string) *Element[string, struct{}] {
+	h := t.hash.Hash(key)
+
+	for s := t.head; s != nil; s = s.next {
+		if s.key == h&t.mask {
+			return s.list.find(h, key)
+		}
+	}
+
+	return nil
+}
+
+// BenchmarkLookup сравнивает поиск сегмента по списку сегментов
+// с поиском по массиву сегментов. Сегментов не меньше, чем строк.
+func BenchmarkLookup(b *testing.B) {
+	finds := []struct {
+		name string
+		find func(*Table[string, struct{}], string) *Element[string, struct{}]
+	}{
+		{"chain", chainFind},
+		{"array", (*Table[string, struct{}]).Find},
+	}
+
+	for _, n := range []int{10000, 100000, 1000000} {
+		bits := 1
+		for 1<<bits < n {
+			bits++
+		}
+
+		tbl := NewTable(FNV1a{}, WithBits[string, struct{}](bits))
+		keys := make([]string, n)
+
+		for i := range keys {
+			keys[i] = "key-" + strconv.Itoa(i)
+			tbl.Put(keys[i], struct{}{})
+		}
+
+		segments := 0
+		for s := tbl.head; s != nil; s = s.next {
+			segments++
+		}
+
+		for _, f := range finds {
+			b.Run(strconv.Itoa(n)+"/"+f.name, func(b *testing.B) {
+				b.ReportMetric(float64(segments), "segments")
+
+				for i := 0; i < b.N; i++ {
+					if f.find(tbl, keys[i%len(keys)]) == nil {
+						b.Fatal("элемент не найден")
+					}
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package hashtable
+
+import (
+	"sync"
+	"time"
+)
+
+// WithTTL задает срок жизни элементов по умолчанию: элемент,
+// добавленный или записанный Put и Update, истекает через ttl.
+// Значение 0 означает, что элементы не истекают.
+//
+// Истекший элемент удаляется, когда к нему обращаются
+// (Find, Get, Put и другие методы поиска), или очисткой Sweep.
+// До удаления он учитывается в Len, но не выдается итераторами.
+func WithTTL[K comparable, V any](ttl time.Duration) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.ttl = ttl
+	}
+}
+
+// WithClock задает часы, по которым отсчитывается срок жизни элементов.
+// По умолчанию используются системные часы.
+func WithClock[K comparable, V any](clock Clock) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.clock = clock
+	}
+}
+
+// WithExpire задает функцию, которая вызывается для каждого истекшего
+// элемента после его удаления из таблицы.
+func WithExpire[K comparable, V any](fn func(key K, value V)) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.expire = fn
+	}
+}
+
+// PutTTL записывает значение по ключу со сроком жизни ttl.
+// Если ttl не положителен, то элемент не истекает.
+// Если элемента с таким ключом нет, то добавляет его.
+func (t *Table[K, V]) PutTTL(key K, value V, ttl time.Duration) {
+	key = t.normalize(key)
+	t.rehash(rehashStep)
+
+	h, _, e := t.search(key)
+	if e != nil {
+		e.value = value
+		e.expires = t.deadline(ttl)
+		t.touch(e)
+
+		return
+	}
+
+	t.insert(h, key, value, ttl)
+}
+
+// SetTTL задает срок жизни существующего элемента, отсчитываемый от текущего времени.
+// Если ttl не положителен, то элемент больше не истекает.
+// Возвращает false, если элемента нет.
+func (t *Table[K, V]) SetTTL(key K, ttl time.Duration) bool {
+	_, _, e := t.search(t.normalize(key))
+	if e == nil {
+		return false
+	}
+
+	e.expires = t.deadline(ttl)
+
+	return true
+}
+
+// TTL возвращает оставшийся срок жизни элемента и true, если элемент существует.
+// Для элемента, который не истекает, возвращает 0.
+func (t *Table[K, V]) TTL(key K) (time.Duration, bool) {
+	_, _, e := t.search(t.normalize(key))
+	if e == nil {
+		return 0, false
+	}
+
+	if e.expires == 0 {
+		return 0, true
+	}
+
+	return time.Duration(e.expires - t.opts.clock.Now().UnixNano()), true
+}
+
+// Sweep удаляет из таблицы все истекшие элементы.
+// Возвращает количество удаленных элементов.
+func (t *Table[K, V]) Sweep() int {
+	now := t.opts.clock.Now().UnixNano()
+
+	// Сначала собираю истекшие элементы,
+	// так как удаление меняет списки сегментов
+	var expired []*Element[K, V]
+
+	for s := t.head; s != nil; s = s.next {
+		for e := s.list; e != nil; e = e.next {
+			if e.expiredAt(now) {
+				expired = append(expired, e)
+			}
+		}
+	}
+
+	for _, e := range expired {
+		t.expire(e)
+	}
+
+	return len(expired)
+}
+
+// StartSweeper запускает в отдельной горутине очистку Sweep
+// каждые interval по часам таблицы. Таблица не защищена от
+// одновременного доступа, поэтому очистка выполняется под блокировкой mu,
+// которую должен использовать и остальной код, обращающийся к таблице.
+// Возвращает функцию, которая останавливает очистку
+// и дожидается завершения горутины.
+func (t *Table[K, V]) StartSweeper(interval time.Duration, mu sync.Locker) (stop func()) {
+	return startSweeper(t.opts.clock, interval, func() {
+		mu.Lock()
+		defer mu.Unlock()
+
+		t.Sweep()
+	})
+}
+
+// startSweeper вызывает sweep каждые interval по часам clock,
+// пока не будет вызвана возвращаемая функция остановки.
+func startSweeper(clock Clock, interval time.Duration, sweep func()) func() {
+	done := make(chan struct{})
+	exited := make(chan struct{})
+
+	go func() {
+		defer close(exited)
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-clock.After(interval):
+				sweep()
+			}
+		}
+	}()
+
+	var once sync.Once
+
+	return func() {
+		once.Do(func() { close(done) })
+		<-exited
+	}
+}
+
+// search возвращает хэш значение ключа, соответствующий ему сегмент
+// и элемент с ключом, как lookup, но истекший элемент
+// удаляет из таблицы и не возвращает.
+func (t *Table[K, V]) search(key K) (uint64, *Segment[K, V], *Element[K, V]) {
+	h, s, e := t.lookup(key)
+	if e != nil && t.expired(e) {
+		t.expire(e)
+		return h, nil, nil
+	}
+
+	return h, s, e
+}
+
+// deadline возвращает время истечения элемента со сроком жизни ttl
+// в наносекундах или 0, если элемент не истекает.
+func (t *Table[K, V]) deadline(ttl time.Duration) int64 {
+	if ttl <= 0 {
+		return 0
+	}
+
+	return t.opts.clock.Now().Add(ttl).UnixNano()
+}
+
+// expired возвращает true, если срок жизни элемента истек.
+func (t *Table[K, V]) expired(e *Element[K, V]) bool {
+	// Часы запрашиваю, только если элемент может истечь
+	return e.expires != 0 && e.expiredAt(t.opts.clock.Now().UnixNano())
+}
+
+// expire удаляет истекший элемент и вызывает функцию WithExpire.
+func (t *Table[K, V]) expire(e *Element[K, V]) {
+	t.remove(e.hash, e.key)
+
+	if t.onExpire != nil {
+		t.onExpire(e.key, e.value)
+	}
+}
+
+// Expires возвращает время истечения элемента
+// или нулевое время, если элемент не истекает.
+func (e *Element[K, V]) Expires() time.Time {
+	if e.expires == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(0, e.expires)
+}
+
+// expiredAt возвращает true, если элемент истекает
+// не позже времени now в наносекундах.
+func (e *Element[K, V]) expiredAt(now int64) bool {
+	return e.expires != 0 && now >= e.expires
+}
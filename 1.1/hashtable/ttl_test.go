@@ -0,0 +1,240 @@
+package hashtable
+
+import (
+	"maps"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// expiredKeys собирает ключи, для которых вызвана функция WithExpire.
+type expiredKeys struct {
+	keys []string
+}
+
+func (x *expiredKeys) option() Option[string, int] {
+	return WithExpire(func(key string, value int) {
+		x.keys = append(x.keys, key)
+	})
+}
+
+func (x *expiredKeys) check(t *testing.T, want ...string) {
+	t.Helper()
+
+	slices.Sort(x.keys)
+
+	if !slices.Equal(x.keys, want) {
+		t.Fatalf("истекли %v, want %v", x.keys, want)
+	}
+}
+
+func TestTTLLazyExpiry(t *testing.T) {
+	clock := NewManualClock(time.Unix(0, 0))
+
+	var x expiredKeys
+
+	tbl := NewTable(FNV1a{},
+		WithTTL[string, int](time.Minute),
+		WithClock[string, int](clock),
+		x.option(),
+	)
+
+	tbl.Put("a", 1)
+	tbl.Put("b", 2)
+	tbl.PutTTL("c", 3, 0)
+
+	clock.Advance(time.Minute - time.Nanosecond)
+
+	if v, ok := tbl.Get("a"); !ok || v != 1 {
+		t.Fatalf("Get(a) до истечения = %d, %v", v, ok)
+	}
+
+	x.check(t)
+
+	// Элемент истекает ровно в момент окончания срока жизни
+	clock.Advance(time.Nanosecond)
+
+	// До обращения истекшие элементы учитываются в Len,
+	// но не выдаются итераторами
+	if tbl.Len() != 3 {
+		t.Errorf("Len() = %d до обращения, want 3", tbl.Len())
+	}
+
+	if got := maps.Collect(tbl.All()); !maps.Equal(got, map[string]int{"c": 3}) {
+		t.Errorf("All() = %v, want map[c:3]", got)
+	}
+
+	if _, ok := tbl.Get("a"); ok {
+		t.Error("Get(a) нашел истекший элемент")
+	}
+
+	x.check(t, "a")
+
+	if e := tbl.Find("b"); e != nil {
+		t.Errorf("Find(b) = %v, want nil", e)
+	}
+
+	x.check(t, "a", "b")
+
+	if tbl.Len() != 1 {
+		t.Errorf("Len() = %d после обращения, want 1", tbl.Len())
+	}
+
+	// Put продлевает срок жизни, а элемент без срока не истекает
+	tbl.Put("d", 4)
+	clock.Advance(30 * time.Second)
+	tbl.Put("d", 5)
+	clock.Advance(45 * time.Second)
+
+	if v, ok := tbl.Get("d"); !ok || v != 5 {
+		t.Errorf("Get(d) после Put = %d, %v, want 5, true", v, ok)
+	}
+
+	if e := tbl.Find("c"); e == nil || !e.Expires().IsZero() {
+		t.Errorf("Find(c) = %v, want элемент без срока жизни", e)
+	}
+
+	x.check(t, "a", "b")
+}
+
+func TestSetTTL(t *testing.T) {
+	clock := NewManualClock(time.Unix(100, 0))
+	tbl := NewTable(FNV1a{}, WithClock[string, int](clock))
+
+	tbl.Put("a", 1)
+
+	if ttl, ok := tbl.TTL("a"); !ok || ttl != 0 {
+		t.Errorf("TTL(a) без срока = %v, %v, want 0, true", ttl, ok)
+	}
+
+	if !tbl.SetTTL("a", 10*time.Second) {
+		t.Fatal("SetTTL(a) = false")
+	}
+
+	clock.Advance(4 * time.Second)
+
+	if ttl, ok := tbl.TTL("a"); !ok || ttl != 6*time.Second {
+		t.Errorf("TTL(a) = %v, %v, want 6s, true", ttl, ok)
+	}
+
+	if got, want := tbl.Find("a").Expires(), time.Unix(110, 0); !got.Equal(want) {
+		t.Errorf("Expires() = %v, want %v", got, want)
+	}
+
+	// Неположительный срок отменяет истечение
+	tbl.SetTTL("a", -time.Second)
+	clock.Advance(time.Hour)
+
+	if ttl, ok := tbl.TTL("a"); !ok || ttl != 0 {
+		t.Errorf("TTL(a) после отмены = %v, %v, want 0, true", ttl, ok)
+	}
+
+	tbl.SetTTL("a", time.Second)
+	clock.Advance(time.Second)
+
+	if tbl.SetTTL("a", time.Minute) {
+		t.Error("SetTTL продлил истекший элемент")
+	}
+
+	if _, ok := tbl.TTL("a"); ok {
+		t.Error("TTL(a) нашел истекший элемент")
+	}
+
+	if tbl.SetTTL("missing", time.Minute) {
+		t.Error("SetTTL(missing) = true")
+	}
+
+	checkTable(t, tbl, map[string]int{})
+}
+
+func TestWithExpire(t *testing.T) {
+	clock := NewManualClock(time.Unix(0, 0))
+
+	var x expiredKeys
+
+	// Без WithTTL истекают только элементы PutTTL
+	tbl := NewTable(FNV1a{}, WithClock[string, int](clock), x.option())
+
+	tbl.Put("a", 1)
+	tbl.PutTTL("b", 2, time.Second)
+	tbl.PutTTL("c", 3, time.Second)
+	tbl.PutTTL("d", 4, time.Minute)
+
+	// Удаление и перезапись не вызывают функцию истечения
+	tbl.Delete("c")
+	tbl.Put("d", 5)
+
+	clock.Advance(time.Hour)
+
+	if n := tbl.Sweep(); n != 1 {
+		t.Errorf("Sweep() = %d, want 1", n)
+	}
+
+	x.check(t, "b")
+	checkTable(t, tbl, map[string]int{"a": 1, "d": 5})
+
+	if n := tbl.Sweep(); n != 0 {
+		t.Errorf("повторный Sweep() = %d, want 0", n)
+	}
+}
+
+// waitFor ждет, пока cond не вернет true.
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+
+	for deadline := time.Now().Add(5 * time.Second); !cond(); time.Sleep(time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatalf("не дождался: %s", what)
+		}
+	}
+}
+
+func TestStartSweeper(t *testing.T) {
+	clock := NewManualClock(time.Unix(0, 0))
+	expired := make(chan string, 10)
+
+	tbl := NewTable(FNV1a{},
+		WithTTL[string, int](30*time.Second),
+		WithClock[string, int](clock),
+		WithExpire(func(key string, value int) { expired <- key }),
+	)
+
+	var mu sync.Mutex
+
+	mu.Lock()
+	tbl.Put("a", 1)
+	tbl.PutTTL("b", 2, 0)
+	mu.Unlock()
+
+	stop := tbl.StartSweeper(time.Minute, &mu)
+
+	// Часы переводятся, только когда очистка ждет,
+	// иначе она отсчитала бы интервал от нового времени
+	waitFor(t, "ожидание очистки", func() bool { return clock.Waiters() == 1 })
+	clock.Advance(time.Minute)
+
+	if key := <-expired; key != "a" {
+		t.Errorf("истек %q, want a", key)
+	}
+
+	waitFor(t, "следующее ожидание очистки", func() bool { return clock.Waiters() == 1 })
+
+	mu.Lock()
+	if tbl.Len() != 1 {
+		t.Errorf("Len() = %d после очистки, want 1", tbl.Len())
+	}
+
+	tbl.Put("c", 3)
+	mu.Unlock()
+
+	stop()
+	stop()
+
+	// После остановки элементы истекают только при обращении
+	clock.Advance(time.Hour)
+
+	if tbl.Len() != 2 || len(expired) != 0 {
+		t.Errorf("Len() = %d, истекло %d после остановки, want 2, 0", tbl.Len(), len(expired))
+	}
+}
@@ -0,0 +1,177 @@
+package hashtest
+
+import (
+	"math"
+	"math/bits"
+)
+
+// AvalancheResult содержит результат проверки лавинного эффекта:
+// при изменении одного бита ключа каждый бит результата
+// должен меняться с вероятностью 1/2.
+type AvalancheResult struct {
+	// Matrix[i][j] — доля ключей, у которых изменение бита i ключа
+	// изменило бит j результата.
+	Matrix [][]float64 `json:"matrix"`
+	// MeanBias — среднее отклонение доли от 1/2, отнесенное к 1/2:
+	// 0 для идеальной функции, 1, если биты не меняются или меняются всегда.
+	MeanBias float64 `json:"mean_bias"`
+	// MaxBias — наибольшее такое отклонение.
+	MaxBias float64 `json:"max_bias"`
+	// MeanFlips — средняя доля изменившихся бит результата.
+	MeanFlips float64 `json:"mean_flips"`
+}
+
+// Avalanche меняет по очереди каждый бит каждого ключа
+// и считает, как часто меняется каждый из width бит результата.
+func Avalanche(fn Func, width int, keys []string) AvalancheResult {
+	in := inputBits(keys)
+	counts := make([][]int, in)
+	for i := range counts {
+		counts[i] = make([]int, width)
+	}
+
+	flips := 0
+
+	forEachFlip(fn, width, keys, func(i int, diff uint) {
+		flips += bits.OnesCount(diff)
+
+		// Прохожусь по изменившимся битам результата
+		for ; diff != 0; diff &= diff - 1 {
+			counts[i][bits.TrailingZeros(diff)]++
+		}
+	})
+
+	var r AvalancheResult
+	if len(keys) == 0 || in == 0 {
+		return r
+	}
+
+	r.Matrix = make([][]float64, in)
+	for i := range counts {
+		r.Matrix[i] = make([]float64, width)
+
+		for j, c := range counts[i] {
+			p := float64(c) / float64(len(keys))
+			bias := math.Abs(p-0.5) * 2
+
+			r.Matrix[i][j] = p
+			r.MeanBias += bias
+			r.MaxBias = max(r.MaxBias, bias)
+		}
+	}
+
+	r.MeanBias /= float64(in * width)
+	r.MeanFlips = float64(flips) / float64(len(keys)*in*width)
+
+	return r
+}
+
+// IndependenceResult содержит результат проверки независимости бит
+// (bit independence criterion): при изменении одного бита ключа
+// изменения любых двух бит результата не должны быть связаны.
+type IndependenceResult struct {
+	// MeanCorrelation — среднее абсолютное значение коэффициента корреляции
+	// изменений пар бит результата.
+	MeanCorrelation float64 `json:"mean_correlation"`
+	// MaxCorrelation — наибольшее абсолютное значение коэффициента корреляции.
+	MaxCorrelation float64 `json:"max_correlation"`
+	// Constant — количество пар бит ключа и результата, для которых бит
+	// результата не меняется никогда или меняется всегда.
+	// Для таких бит корреляция не определена и не учитывается.
+	Constant int `json:"constant"`
+}
+
+// Independence меняет по очереди каждый бит каждого ключа
+// и для каждой пары из width бит результата считает
+// коэффициент корреляции их изменений.
+func Independence(fn Func, width int, keys []string) IndependenceResult {
+	in := inputBits(keys)
+
+	// single[i][j] — сколько раз менялся бит j,
+	// pair[i][j][k] — сколько раз одновременно менялись биты j и k
+	single := make([][]int, in)
+	pair := make([][][]int, in)
+
+	for i := range single {
+		single[i] = make([]int, width)
+		pair[i] = make([][]int, width)
+
+		for j := range pair[i] {
+			pair[i][j] = make([]int, width)
+		}
+	}
+
+	forEachFlip(fn, width, keys, func(i int, diff uint) {
+		for d := diff; d != 0; d &= d - 1 {
+			j := bits.TrailingZeros(d)
+			single[i][j]++
+
+			for rest := d & (d - 1); rest != 0; rest &= rest - 1 {
+				pair[i][j][bits.TrailingZeros(rest)]++
+			}
+		}
+	})
+
+	var r IndependenceResult
+
+	n := float64(len(keys))
+	pairs := 0
+
+	for i := range single {
+		for j := range width {
+			cj := float64(single[i][j])
+			if cj == 0 || cj == n {
+				r.Constant++
+				continue
+			}
+
+			for k := j + 1; k < width; k++ {
+				ck := float64(single[i][k])
+				if ck == 0 || ck == n {
+					continue
+				}
+
+				cjk := float64(pair[i][j][k])
+				corr := math.Abs((n*cjk - cj*ck) / math.Sqrt(cj*(n-cj)*ck*(n-ck)))
+
+				r.MeanCorrelation += corr
+				r.MaxCorrelation = max(r.MaxCorrelation, corr)
+				pairs++
+			}
+		}
+	}
+
+	if pairs > 0 {
+		r.MeanCorrelation /= float64(pairs)
+	}
+
+	return r
+}
+
+// forEachFlip вызывает fn для каждого ключа и каждого бита ключа
+// с номером бита и маской из width бит результата, изменившихся
+// при изменении этого бита.
+func forEachFlip(fn Func, width int, keys []string, visit func(i int, diff uint)) {
+	m := mask(width)
+
+	for _, key := range keys {
+		h := fn(key) & m
+		buf := []byte(key)
+
+		for i := range len(buf) * 8 {
+			buf[i/8] ^= 1 << (i % 8)
+			visit(i, (fn(string(buf))&m)^h)
+			buf[i/8] ^= 1 << (i % 8)
+		}
+	}
+}
+
+// inputBits возвращает количество бит ключа.
+// Все ключи проверки имеют одинаковую длину.
+func inputBits(keys []string) int {
+	if len(keys) == 0 {
+		return 0
+	}
+
+	return len(keys[0]) * 8
+}
@@ -0,0 +1,98 @@
+package hashtest
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"strings"
+)
+
+// Corpus представляет именованный набор различных ключей.
+type Corpus struct {
+	Name string
+	Keys []string
+}
+
+// syllables содержит слоги, из которых составляются слова.
+var syllables = []string{
+	"ка", "ро", "ма", "ли", "на", "то", "ве", "ст", "ра", "по",
+	"ны", "ко", "ре", "да", "ми", "ло", "се", "тр", "ен", "ов",
+	"an", "er", "in", "th", "on", "re", "at", "es", "or", "te",
+	"al", "ed", "is", "it", "ar", "st", "to", "nt", "ng", "se",
+}
+
+// Words возвращает набор из n различных слов, составленных
+// из 1–5 слогов русского и английского языков.
+func Words(n int) Corpus {
+	rng := rand.New(rand.NewPCG(2, 2))
+
+	return unique("words", n, func(_ int) string {
+		var b strings.Builder
+
+		for range 1 + rng.IntN(5) {
+			b.WriteString(syllables[rng.IntN(len(syllables))])
+		}
+
+		return b.String()
+	})
+}
+
+// hosts и paths содержат части адресов набора URLs.
+var (
+	hosts = []string{"example.com", "api.example.com", "shop.example.org", "ru.wikipedia.org", "github.com"}
+	paths = []string{"users", "posts", "items", "search", "wiki", "orders", "files", "static/img"}
+)
+
+// URLs возвращает набор из n различных адресов с общими префиксами,
+// которые отличаются путем, номером и параметрами запроса.
+func URLs(n int) Corpus {
+	rng := rand.New(rand.NewPCG(3, 3))
+
+	return unique("urls", n, func(_ int) string {
+		return fmt.Sprintf("https://%s/%s/%d?page=%d",
+			hosts[rng.IntN(len(hosts))], paths[rng.IntN(len(paths))], rng.IntN(1_000_000), rng.IntN(50))
+	})
+}
+
+// SequentialIDs возвращает набор из n идентификаторов,
+// отличающихся последними цифрами: id-00000000, id-00000001 и т. д.
+func SequentialIDs(n int) Corpus {
+	return unique("ids", n, func(i int) string {
+		return fmt.Sprintf("id-%08d", i)
+	})
+}
+
+// ReadCorpus возвращает набор различных непустых строк из r.
+func ReadCorpus(name string, r io.Reader) (Corpus, error) {
+	c := Corpus{Name: name}
+	seen := make(map[string]bool)
+
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, 1<<20)
+
+	for sc.Scan() {
+		if key := strings.TrimSpace(sc.Text()); key != "" && !seen[key] {
+			seen[key] = true
+			c.Keys = append(c.Keys, key)
+		}
+	}
+
+	return c, sc.Err()
+}
+
+// unique возвращает набор из n различных ключей, созданных next.
+// Повторяющиеся ключи пропускаются.
+func unique(name string, n int, next func(i int) string) Corpus {
+	c := Corpus{Name: name, Keys: make([]string, 0, n)}
+	seen := make(map[string]bool, n)
+
+	for i := 0; len(c.Keys) < n; i++ {
+		if key := next(i); !seen[key] {
+			seen[key] = true
+			c.Keys = append(c.Keys, key)
+		}
+	}
+
+	return c
+}
@@ -0,0 +1,132 @@
+package hashtest
+
+import (
+	"math/bits"
+	"time"
+)
+
+// DistributionResult содержит распределение ключей набора по сегментам,
+// номер которых — младшие биты хэш значения.
+type DistributionResult struct {
+	Corpus       string  `json:"corpus"`
+	Keys         int     `json:"keys"`
+	Buckets      int     `json:"buckets"`       // Количество сегментов
+	Empty        int     `json:"empty"`         // Количество пустых сегментов
+	LongestChain int     `json:"longest_chain"` // Наибольшее количество ключей в сегменте
+	Collisions   int     `json:"collisions"`    // Ключи, хэш значение которых совпало с предыдущим ключом
+	ChiSquared   float64 `json:"chi_squared"`   // Статистика хи-квадрат
+	// Uniformity — хи-квадрат, отнесенный к числу степеней свободы:
+	// близко к 1 для случайного распределения.
+	Uniformity float64 `json:"uniformity"`
+}
+
+// Distribution раскладывает ключи набора по 2^bucketBits сегментам.
+// Если bucketBits равен 0, то сегментов не меньше, чем ключей.
+// Количество сегментов ограничивается разрядностью результата bits.
+func Distribution(fn Func, bits int, c Corpus, bucketBits int) DistributionResult {
+	if bucketBits <= 0 {
+		bucketBits = log2(len(c.Keys))
+	}
+
+	bucketBits = min(bucketBits, bits, 30)
+
+	r := DistributionResult{Corpus: c.Name, Keys: len(c.Keys), Buckets: 1 << bucketBits}
+
+	counts := make([]int, r.Buckets)
+	seen := make(map[uint]struct{}, len(c.Keys))
+	m := mask(bits)
+
+	for _, key := range c.Keys {
+		h := fn(key) & m
+
+		if _, ok := seen[h]; ok {
+			r.Collisions++
+		}
+		seen[h] = struct{}{}
+
+		counts[h&uint(r.Buckets-1)]++
+	}
+
+	if r.Keys == 0 {
+		return r
+	}
+
+	expected := float64(r.Keys) / float64(r.Buckets)
+	for _, n := range counts {
+		if n == 0 {
+			r.Empty++
+		}
+
+		r.LongestChain = max(r.LongestChain, n)
+
+		d := float64(n) - expected
+		r.ChiSquared += d * d / expected
+	}
+
+	if r.Buckets > 1 {
+		r.Uniformity = r.ChiSquared / float64(r.Buckets-1)
+	}
+
+	return r
+}
+
+// sink принимает хэш значения, чтобы компилятор не убрал их вычисление.
+var sink uint
+
+// throughputTime задает наименьшее время измерения Throughput.
+const throughputTime = 100 * time.Millisecond
+
+// ThroughputResult содержит скорость вычисления хэш значений ключей набора.
+type ThroughputResult struct {
+	Corpus      string  `json:"corpus"`
+	NsPerOp     float64 `json:"ns_per_op"`     // Время вычисления одного хэш значения
+	MBPerSecond float64 `json:"mb_per_second"` // Объем ключей, обрабатываемый за секунду
+}
+
+// Throughput измеряет время вычисления хэш значений ключей набора.
+func Throughput(fn Func, c Corpus) ThroughputResult {
+	r := ThroughputResult{Corpus: c.Name}
+	if len(c.Keys) == 0 {
+		return r
+	}
+
+	size := 0
+	for _, key := range c.Keys {
+		size += len(key)
+	}
+
+	// Удваиваю количество вычислений, пока измерение
+	// не займет хотя бы throughputTime
+	var elapsed time.Duration
+	n := len(c.Keys)
+
+	for {
+		start := time.Now()
+		for i := range n {
+			sink += fn(c.Keys[i%len(c.Keys)])
+		}
+
+		if elapsed = time.Since(start); elapsed >= throughputTime {
+			break
+		}
+
+		n *= 2
+	}
+
+	r.NsPerOp = float64(elapsed.Nanoseconds()) / float64(n)
+	if r.NsPerOp > 0 {
+		// Средний размер ключа в байтах за наносекунду переводится в МБ/с
+		r.MBPerSecond = float64(size) / float64(len(c.Keys)) / r.NsPerOp * 1e3
+	}
+
+	return r
+}
+
+// log2 возвращает наименьшее b, при котором 2^b не меньше n.
+func log2(n int) int {
+	if n <= 1 {
+		return 0
+	}
+
+	return bits.Len(uint(n - 1))
+}
@@ -0,0 +1,83 @@
+// Package hashtest проверяет качество хэш функций строк:
+// лавинный эффект, независимость бит результата,
+// распределение ключей реалистичных наборов по сегментам
+// и скорость вычисления.
+package hashtest
+
+import "math/rand/v2"
+
+// Func представляет проверяемую хэш функцию.
+type Func func(string) uint
+
+// Config содержит параметры проверки.
+type Config struct {
+	Samples    int      // Количество случайных ключей для лавинных проверок
+	KeyLen     int      // Длина случайного ключа в байтах
+	BucketBits int      // Разрядность номера сегмента, 0 — по размеру набора
+	Corpora    []Corpus // Наборы ключей для проверки распределения и скорости
+	Seed       uint64   // Начальное значение генератора случайных ключей
+}
+
+// DefaultConfig возвращает параметры проверки по умолчанию
+// с наборами слов, URL и последовательных идентификаторов из n ключей.
+func DefaultConfig(n int) Config {
+	return Config{
+		Samples: 2000,
+		KeyLen:  8,
+		Corpora: []Corpus{Words(n), URLs(n), SequentialIDs(n)},
+		Seed:    1,
+	}
+}
+
+// Report содержит результаты всех проверок хэш функции.
+type Report struct {
+	Name         string               `json:"name"`
+	Bits         int                  `json:"bits"`         // Разрядность результата
+	Avalanche    AvalancheResult      `json:"avalanche"`    // Лавинный эффект
+	Independence IndependenceResult   `json:"independence"` // Независимость бит результата
+	Distribution []DistributionResult `json:"distribution"` // Распределение по наборам ключей
+	Throughput   []ThroughputResult   `json:"throughput"`   // Скорость по наборам ключей
+}
+
+// Run выполняет все проверки хэш функции fn,
+// результат которой содержит bits значимых бит.
+func Run(name string, fn Func, bits int, cfg Config) Report {
+	r := Report{Name: name, Bits: bits}
+
+	keys := randomKeys(cfg.Samples, cfg.KeyLen, cfg.Seed)
+	r.Avalanche = Avalanche(fn, bits, keys)
+	r.Independence = Independence(fn, bits, keys)
+
+	for _, c := range cfg.Corpora {
+		r.Distribution = append(r.Distribution, Distribution(fn, bits, c, cfg.BucketBits))
+		r.Throughput = append(r.Throughput, Throughput(fn, c))
+	}
+
+	return r
+}
+
+// randomKeys возвращает n случайных ключей длины size.
+func randomKeys(n, size int, seed uint64) []string {
+	rng := rand.New(rand.NewPCG(seed, seed))
+	keys := make([]string, n)
+
+	buf := make([]byte, size)
+	for i := range keys {
+		for j := range buf {
+			buf[j] = byte(rng.Uint32())
+		}
+
+		keys[i] = string(buf)
+	}
+
+	return keys
+}
+
+// mask возвращает маску младших bits бит.
+func mask(bits int) uint {
+	if bits >= 64 {
+		return ^uint(0)
+	}
+
+	return 1<<bits - 1
+}
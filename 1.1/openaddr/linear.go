@@ -0,0 +1,203 @@
+package openaddr
+
+import (
+	"iter"
+
+	"github.com/polRk/data_structures_and_algorithms/1.1/hashtable"
+)
+
+// Linear представляет хэш-таблицу с линейным пробированием:
+// при коллизии элемент занимает следующую свободную ячейку.
+// Удаление сдвигает следующие элементы назад, поэтому
+// таблица не хранит отметок об удаленных элементах.
+type Linear[K comparable, V any] struct {
+	slots []linearSlot[K, V]
+	mask  uint64
+	len   int
+	hash  hashtable.Hasher[K]
+}
+
+// linearSlot представляет ячейку таблицы Linear.
+type linearSlot[K comparable, V any] struct {
+	key   K
+	value V
+	hash  uint64 // Перемешанное хэш значение ключа
+	used  bool   // Ячейка занята
+}
+
+// NewLinear возвращает пустую таблицу с линейным пробированием,
+// использующую переданную хэш функцию.
+func NewLinear[K comparable, V any](hash hashtable.Hasher[K]) *Linear[K, V] {
+	return &Linear[K, V]{
+		slots: make([]linearSlot[K, V], minCapacity),
+		mask:  minCapacity - 1,
+		hash:  hash,
+	}
+}
+
+// Len возвращает количество элементов в таблице.
+func (t *Linear[K, V]) Len() int {
+	return t.len
+}
+
+// Put записывает значение по ключу.
+// Если элемента с таким ключом нет, то добавляет его.
+func (t *Linear[K, V]) Put(key K, value V) {
+	h, i, ok := t.find(key)
+	if ok {
+		t.slots[i].value = value
+		return
+	}
+
+	t.insert(h, i, key, value)
+}
+
+// Get возвращает значение по ключу и true,
+// если элемент существует.
+func (t *Linear[K, V]) Get(key K) (V, bool) {
+	if _, i, ok := t.find(key); ok {
+		return t.slots[i].value, true
+	}
+
+	var zero V
+	return zero, false
+}
+
+// GetOrInsert возвращает значение существующего элемента и true.
+// Если элемента нет, то добавляет переданное значение
+// и возвращает его и false.
+func (t *Linear[K, V]) GetOrInsert(key K, value V) (V, bool) {
+	h, i, ok := t.find(key)
+	if ok {
+		return t.slots[i].value, true
+	}
+
+	t.insert(h, i, key, value)
+
+	return value, false
+}
+
+// Update заменяет значение по ключу результатом fn.
+// Если элемента нет, то fn получает нулевое значение,
+// а результат добавляется в таблицу.
+// Возвращает новое значение.
+func (t *Linear[K, V]) Update(key K, fn func(old V) V) V {
+	h, i, ok := t.find(key)
+	if ok {
+		t.slots[i].value = fn(t.slots[i].value)
+		return t.slots[i].value
+	}
+
+	var zero V
+	value := fn(zero)
+	t.insert(h, i, key, value)
+
+	return value
+}
+
+// Delete удаляет элемент из таблицы.
+// Возвращает значение удаленного элемента и true,
+// если элемент существовал.
+func (t *Linear[K, V]) Delete(key K) (V, bool) {
+	_, i, ok := t.find(key)
+	if !ok {
+		var zero V
+		return zero, false
+	}
+
+	value := t.slots[i].value
+
+	// Сдвигаю назад следующие элементы, которые без удаленного
+	// элемента оказались бы недостижимы от своей начальной ячейки
+	for j := (i + 1) & t.mask; t.slots[j].used; j = (j + 1) & t.mask {
+		home := t.slots[j].hash & t.mask
+
+		// Элемент остается, если его начальная ячейка
+		// циклически лежит в промежутке (i, j]
+		if i <= j {
+			if i < home && home <= j {
+				continue
+			}
+		} else if i < home || home <= j {
+			continue
+		}
+
+		t.slots[i] = t.slots[j]
+		i = j
+	}
+
+	t.slots[i] = linearSlot[K, V]{}
+	t.len--
+
+	return value, true
+}
+
+// All возвращает итератор по парам ключ-значение таблицы.
+// Если таблица изменяется во время итерации, то элементы
+// могут быть пропущены или выданы повторно.
+func (t *Linear[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for _, s := range t.slots {
+			if s.used && !yield(s.key, s.value) {
+				return
+			}
+		}
+	}
+}
+
+// find возвращает перемешанное хэш значение ключа, индекс ячейки с ключом и true.
+// Если элемента нет, то вместо индекса ячейки с ключом возвращает
+// индекс свободной ячейки, в которую его следует добавить, и false.
+func (t *Linear[K, V]) find(key K) (uint64, uint64, bool) {
+	h := mix(t.hash.Hash(key))
+
+	for i := h & t.mask; ; i = (i + 1) & t.mask {
+		s := &t.slots[i]
+
+		if !s.used {
+			return h, i, false
+		}
+
+		if s.hash == h && s.key == key {
+			return h, i, true
+		}
+	}
+}
+
+// insert добавляет элемент в свободную ячейку i,
+// найденную find. Если таблица заполнена на 3/4, то она
+// увеличивается вдвое и ячейка ищется заново.
+func (t *Linear[K, V]) insert(h, i uint64, key K, value V) {
+	if (t.len+1)*4 > len(t.slots)*3 {
+		t.resize(len(t.slots) * 2)
+
+		i = h & t.mask
+		for t.slots[i].used {
+			i = (i + 1) & t.mask
+		}
+	}
+
+	t.slots[i] = linearSlot[K, V]{key: key, value: value, hash: h, used: true}
+	t.len++
+}
+
+// resize переносит элементы в массив из n ячеек.
+func (t *Linear[K, V]) resize(n int) {
+	slots := t.slots
+
+	t.slots = make([]linearSlot[K, V], n)
+	t.mask = uint64(n - 1)
+
+	for _, s := range slots {
+		if !s.used {
+			continue
+		}
+
+		i := s.hash & t.mask
+		for t.slots[i].used {
+			i = (i + 1) & t.mask
+		}
+
+		t.slots[i] = s
+	}
+}
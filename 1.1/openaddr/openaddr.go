@@ -0,0 +1,29 @@
+// Package openaddr реализует хэш-таблицы с открытой адресацией:
+// линейное пробирование, Robin Hood и таблицу с управляющими байтами
+// в стиле SwissTable. Все таблицы реализуют hashtable.Interface
+// и используют те же хэш функции, что и hashtable.Table.
+package openaddr
+
+import "github.com/polRk/data_structures_and_algorithms/1.1/hashtable"
+
+// minCapacity задает начальное количество ячеек таблицы.
+const minCapacity = 8
+
+// mix перемешивает биты хэш значения (финализатор MurmurHash3),
+// чтобы хэш функции малой разрядности, например Pearson8,
+// заполняли все биты индекса ячейки.
+func mix(h uint64) uint64 {
+	h ^= h >> 33
+	h *= 0xff51afd7ed558ccd
+	h ^= h >> 33
+	h *= 0xc4ceb9fe1a85ec53
+	h ^= h >> 33
+
+	return h
+}
+
+var (
+	_ hashtable.Interface[string, int] = (*Linear[string, int])(nil)
+	_ hashtable.Interface[string, int] = (*RobinHood[string, int])(nil)
+	_ hashtable.Interface[string, int] = (*Swiss[string, int])(nil)
+)
@@ -0,0 +1,483 @@
+package openaddr
+
+import (
+	"math/rand/v2"
+	"runtime"
+	"strconv"
+	"testing"
+
+	"github.com/polRk/data_structures_and_algorithms/1.1/hashtable"
+)
+
+// inverse возвращает обратное к нечетному a по модулю 2^64.
+func inverse(a uint64) uint64 {
+	// Метод Ньютона удваивает количество верных бит на каждом шаге
+	x := a
+	for range 6 {
+		x *= 2 - a*x
+	}
+
+	return x
+}
+
+// unmix обращает mix, чтобы тест мог задать перемешанное хэш значение ключа.
+func unmix(h uint64) uint64 {
+	// Сдвиг на 33 бита больше половины слова, поэтому h ^= h >> 33
+	// обращает сам себя
+	h ^= h >> 33
+	h *= inverse(0xc4ceb9fe1a85ec53)
+	h ^= h >> 33
+	h *= inverse(0xff51afd7ed558ccd)
+	h ^= h >> 33
+
+	return h
+}
+
+// placed представляет хэш функцию, для которой тест задает
+// перемешанное хэш значение каждого ключа.
+type placed map[string]uint64
+
+func (p placed) Hash(key string) uint64 {
+	return unmix(p[key])
+}
+
+func (p placed) Bits() int {
+	return 64
+}
+
+// implementation описывает проверяемую таблицу.
+type implementation struct {
+	name  string
+	new   func(hashtable.Hasher[string]) hashtable.Interface[string, int]
+	check func(*testing.T, hashtable.Interface[string, int])
+}
+
+var implementations = []implementation{
+	{
+		"linear",
+		func(h hashtable.Hasher[string]) hashtable.Interface[string, int] { return NewLinear[string, int](h) },
+		checkLinear,
+	},
+	{
+		"robinhood",
+		func(h hashtable.Hasher[string]) hashtable.Interface[string, int] { return NewRobinHood[string, int](h) },
+		checkRobinHood,
+	},
+	{
+		"swiss",
+		func(h hashtable.Hasher[string]) hashtable.Interface[string, int] { return NewSwiss[string, int](h) },
+		checkSwiss,
+	},
+}
+
+// checkLinear проверяет, что каждый элемент достижим от своей начальной ячейки
+// без свободных ячеек на пути.
+func checkLinear(t *testing.T, tbl hashtable.Interface[string, int]) {
+	t.Helper()
+
+	l := tbl.(*Linear[string, int])
+	used := 0
+
+	for i, s := range l.slots {
+		if !s.used {
+			continue
+		}
+
+		used++
+
+		if s.hash != mix(l.hash.Hash(s.key)) {
+			t.Fatalf("ячейка %d: неверное хэш значение ключа %q", i, s.key)
+		}
+
+		for j := s.hash & l.mask; j != uint64(i); j = (j + 1) & l.mask {
+			if !l.slots[j].used {
+				t.Fatalf("ключ %q в ячейке %d недостижим: ячейка %d свободна", s.key, i, j)
+			}
+		}
+	}
+
+	if used != l.len {
+		t.Fatalf("занято %d ячеек, len %d", used, l.len)
+	}
+}
+
+// checkRobinHood проверяет расстояния элементов до начальных ячеек:
+// расстояние следующего элемента больше не более чем на 1.
+func checkRobinHood(t *testing.T, tbl hashtable.Interface[string, int]) {
+	t.Helper()
+
+	r := tbl.(*RobinHood[string, int])
+	used := 0
+
+	for i, s := range r.slots {
+		if s.dist == 0 {
+			continue
+		}
+
+		used++
+
+		if want := (uint64(i)-s.hash)&r.mask + 1; uint64(s.dist) != want {
+			t.Fatalf("ключ %q в ячейке %d: расстояние %d, want %d", s.key, i, s.dist, want)
+		}
+
+		if next := r.slots[(uint64(i)+1)&r.mask]; next.dist > s.dist+1 {
+			t.Fatalf("ячейка %d: расстояние %d после %d", i+1, next.dist, s.dist)
+		}
+	}
+
+	if used != r.len {
+		t.Fatalf("занято %d ячеек, len %d", used, r.len)
+	}
+}
+
+// checkSwiss проверяет управляющие байты, поиск каждого ключа
+// и количество ячеек, которые можно занять до роста.
+func checkSwiss(t *testing.T, tbl hashtable.Interface[string, int]) {
+	t.Helper()
+
+	s := tbl.(*Swiss[string, int])
+	full, deleted := 0, 0
+
+	for i, c := range s.ctrl {
+		switch {
+		case c == ctrlEmpty:
+		case c == ctrlDeleted:
+			deleted++
+		default:
+			full++
+
+			key := s.slots[i].key
+			if h2 := byte(mix(s.hash.Hash(key)) & 0x7F); c != h2 {
+				t.Fatalf("ячейка %d: управляющий байт %x, want %x", i, c, h2)
+			}
+
+			if _, j, ok := s.find(key); !ok || j != uint64(i) {
+				t.Fatalf("find(%q) = %d, %v, want %d, true", key, j, ok, i)
+			}
+		}
+	}
+
+	if full != s.len {
+		t.Fatalf("занято %d ячеек, len %d", full, s.len)
+	}
+
+	if want := len(s.ctrl)*7/8 - full - deleted; s.growth != want {
+		t.Fatalf("growth = %d, want %d", s.growth, want)
+	}
+}
+
+// verify сравнивает таблицу с map и проверяет ее устройство.
+func (impl implementation) verify(t *testing.T, tbl hashtable.Interface[string, int], want map[string]int) {
+	t.Helper()
+
+	if tbl.Len() != len(want) {
+		t.Fatalf("Len() = %d, want %d", tbl.Len(), len(want))
+	}
+
+	for k, v := range want {
+		if got, ok := tbl.Get(k); !ok || got != v {
+			t.Fatalf("Get(%q) = %d, %v, want %d, true", k, got, ok, v)
+		}
+	}
+
+	seen := 0
+	for k, v := range tbl.All() {
+		if want[k] != v {
+			t.Fatalf("All() выдал %q = %d, want %d", k, v, want[k])
+		}
+
+		seen++
+	}
+
+	if seen != len(want) {
+		t.Fatalf("All() выдал %d элементов, want %d", seen, len(want))
+	}
+
+	impl.check(t, tbl)
+}
+
+func TestMatchesMap(t *testing.T) {
+	hashers := []struct {
+		name string
+		hash hashtable.Hasher[string]
+	}{
+		// У Pearson8 всего 256 хэш значений, поэтому
+		// ключи часто совпадают по хэш значению
+		{"pearson8", hashtable.Pearson8},
+		{"fnv1a", hashtable.FNV1a{}},
+	}
+
+	const ops = 50000
+
+	for _, impl := range implementations {
+		for _, h := range hashers {
+			t.Run(impl.name+"/"+h.name, func(t *testing.T) {
+				rng := rand.New(rand.NewPCG(3, 4))
+				tbl := impl.new(h.hash)
+				want := make(map[string]int)
+
+				for i := range ops {
+					// Чередую фазы роста и удаления
+					growing := i/5000%2 == 0
+					key := strconv.Itoa(rng.IntN(1000))
+
+					r := rng.IntN(10)
+					if !growing && r < 6 {
+						r = 9
+					}
+
+					switch {
+					case r < 4:
+						tbl.Put(key, i)
+						want[key] = i
+					case r < 5:
+						got := tbl.Update(key, func(old int) int { return old + 1 })
+						want[key]++
+
+						if got != want[key] {
+							t.Fatalf("Update(%q) = %d, want %d", key, got, want[key])
+						}
+					case r < 6:
+						got, ok := tbl.GetOrInsert(key, i)
+						old, exists := want[key]
+
+						if !exists {
+							want[key], old = i, i
+						}
+
+						if got != old || ok != exists {
+							t.Fatalf("GetOrInsert(%q) = %d, %v, want %d, %v", key, got, ok, old, exists)
+						}
+					case r < 8:
+						got, ok := tbl.Get(key)
+						old, exists := want[key]
+
+						if got != old || ok != exists {
+							t.Fatalf("Get(%q) = %d, %v, want %d, %v", key, got, ok, old, exists)
+						}
+					default:
+						got, ok := tbl.Delete(key)
+						old, exists := want[key]
+						delete(want, key)
+
+						if got != old || ok != exists {
+							t.Fatalf("Delete(%q) = %d, %v, want %d, %v", key, got, ok, old, exists)
+						}
+					}
+
+					if i%500 == 0 {
+						impl.verify(t, tbl, want)
+					}
+				}
+
+				impl.verify(t, tbl, want)
+			})
+		}
+	}
+}
+
+func TestWrapAround(t *testing.T) {
+	// Начальные ячейки ключей в массиве из minCapacity ячеек:
+	// "a" и "b" начинаются в последней ячейке, поэтому "b"
+	// переходит в начало массива и сдвигает "c"
+	homes := []struct {
+		key  string
+		home uint64
+	}{
+		{"a", 7},
+		{"b", 7},
+		{"c", 0},
+		{"d", 6},
+		{"e", 7},
+	}
+
+	for _, impl := range implementations[:2] {
+		t.Run(impl.name, func(t *testing.T) {
+			hash := make(placed)
+			for i, h := range homes {
+				hash[h.key] = h.home | uint64(i+1)<<8
+			}
+
+			tbl := impl.new(hash)
+			want := make(map[string]int)
+
+			for i, h := range homes {
+				tbl.Put(h.key, i)
+				want[h.key] = i
+				impl.verify(t, tbl, want)
+			}
+
+			// Удаление сдвигает назад элементы через конец массива
+			for _, key := range []string{"a", "d", "b", "e", "c"} {
+				tbl.Delete(key)
+				delete(want, key)
+				impl.verify(t, tbl, want)
+			}
+		})
+	}
+
+	t.Run("swiss", func(t *testing.T) {
+		// Ключи начинаются в последней группе из двух, и когда она
+		// заполнена, пробирование переходит в первую группу
+		hash := make(placed)
+		for i := range 12 {
+			hash[strconv.Itoa(i)] = 1<<7 | uint64(i) | uint64(i+1)<<16
+		}
+
+		tbl := NewSwiss[string, int](hash)
+		tbl.init(2 * groupSize)
+
+		want := make(map[string]int)
+
+		for i := range 12 {
+			key := strconv.Itoa(i)
+			tbl.Put(key, i)
+			want[key] = i
+			implementations[2].verify(t, tbl, want)
+		}
+
+		if len(tbl.ctrl) != 2*groupSize {
+			t.Fatalf("таблица выросла до %d ячеек", len(tbl.ctrl))
+		}
+
+		// Последняя группа заполнена, поэтому удаление из нее
+		// оставляет отметку, иначе ключи первой группы стали бы недостижимы
+		tbl.Delete("0")
+		delete(want, "0")
+
+		if tbl.ctrl[groupSize] != ctrlDeleted {
+			t.Fatalf("удаление из заполненной группы не оставило отметку")
+		}
+
+		implementations[2].verify(t, tbl, want)
+
+		// Новый ключ последней группы занимает удаленную ячейку
+		hash["new"] = 1<<7 | 0x7F
+		tbl.Put("new", 100)
+		want["new"] = 100
+
+		if _, i, _ := tbl.find("new"); i != groupSize {
+			t.Fatalf("новый ключ в ячейке %d, want %d", i, groupSize)
+		}
+
+		implementations[2].verify(t, tbl, want)
+	})
+}
+
+func TestSwissTombstones(t *testing.T) {
+	tbl := NewSwiss[string, int](hashtable.FNV1a{})
+	want := make(map[string]int)
+
+	const live = 20
+
+	rehashed := 0
+
+	// Добавляю новые ключи и удаляю старые: количество элементов
+	// не меняется, а отметки об удалении накапливаются, пока
+	// перенос без роста не освободит их
+	for i := range 5000 {
+		key := strconv.Itoa(i)
+		size := len(tbl.ctrl)
+		deleted := tombstones(tbl)
+
+		tbl.Put(key, i)
+		want[key] = i
+
+		if len(tbl.ctrl) == size && deleted > 0 && tombstones(tbl) == 0 {
+			rehashed++
+		}
+
+		if i >= live {
+			old := strconv.Itoa(i - live)
+			tbl.Delete(old)
+			delete(want, old)
+		}
+
+		if i%50 == 0 {
+			implementations[2].verify(t, tbl, want)
+		}
+	}
+
+	implementations[2].verify(t, tbl, want)
+
+	if rehashed == 0 {
+		t.Fatal("ни одного переноса без роста")
+	}
+
+	if len(tbl.ctrl) > 4*live {
+		t.Errorf("таблица из %d элементов выросла до %d ячеек", live, len(tbl.ctrl))
+	}
+}
+
+// tombstones возвращает количество отметок об удалении.
+func tombstones(t *Swiss[string, int]) int {
+	n := 0
+	for _, c := range t.ctrl {
+		if c == ctrlDeleted {
+			n++
+		}
+	}
+
+	return n
+}
+
+// heapAlloc возвращает объем занятой памяти после сборки мусора.
+func heapAlloc() uint64 {
+	var m runtime.MemStats
+
+	runtime.GC()
+	runtime.ReadMemStats(&m)
+
+	return m.HeapAlloc
+}
+
+// BenchmarkLookup сравнивает таблицы с открытой адресацией с таблицей
+// со списками элементов по времени поиска и памяти на элемент.
+func BenchmarkLookup(b *testing.B) {
+	tables := []struct {
+		name string
+		new  func() hashtable.Interface[string, struct{}]
+	}{
+		{"table", func() hashtable.Interface[string, struct{}] {
+			return hashtable.NewTable[string, struct{}](hashtable.FNV1a{})
+		}},
+		{"linear", func() hashtable.Interface[string, struct{}] {
+			return NewLinear[string, struct{}](hashtable.FNV1a{})
+		}},
+		{"robinhood", func() hashtable.Interface[string, struct{}] {
+			return NewRobinHood[string, struct{}](hashtable.FNV1a{})
+		}},
+		{"swiss", func() hashtable.Interface[string, struct{}] {
+			return NewSwiss[string, struct{}](hashtable.FNV1a{})
+		}},
+	}
+
+	for _, n := range []int{10000, 1000000} {
+		keys := make([]string, n)
+		for i := range keys {
+			keys[i] = "key-" + strconv.Itoa(i)
+		}
+
+		for _, tt := range tables {
+			// Заполняю таблицу один раз, а не при каждом запуске с новым b.N
+			before := heapAlloc()
+
+			tbl := tt.new()
+			for _, key := range keys {
+				tbl.Put(key, struct{}{})
+			}
+
+			perEntry := float64(heapAlloc()-before) / float64(n)
+
+			b.Run(strconv.Itoa(n)+"/"+tt.name, func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					if _, ok := tbl.Get(keys[i%n]); !ok {
+						b.Fatal("элемент не найден")
+					}
+				}
+
+				b.ReportMetric(perEntry, "B/entry")
+			})
+		}
+	}
+}
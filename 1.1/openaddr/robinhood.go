@@ -0,0 +1,203 @@
+package openaddr
+
+import (
+	"iter"
+
+	"github.com/polRk/data_structures_and_algorithms/1.1/hashtable"
+)
+
+// RobinHood представляет хэш-таблицу с линейным пробированием
+// по схеме Robin Hood: добавляемый элемент вытесняет элемент,
+// который находится ближе к своей начальной ячейке.
+// Это выравнивает длины пробирования и позволяет прекратить
+// поиск отсутствующего ключа раньше.
+type RobinHood[K comparable, V any] struct {
+	slots []robinHoodSlot[K, V]
+	mask  uint64
+	len   int
+	hash  hashtable.Hasher[K]
+}
+
+// robinHoodSlot представляет ячейку таблицы RobinHood.
+type robinHoodSlot[K comparable, V any] struct {
+	key   K
+	value V
+	hash  uint64 // Перемешанное хэш значение ключа
+	dist  uint32 // Расстояние до начальной ячейки плюс 1, 0 для свободной ячейки
+}
+
+// NewRobinHood возвращает пустую таблицу Robin Hood,
+// использующую переданную хэш функцию.
+func NewRobinHood[K comparable, V any](hash hashtable.Hasher[K]) *RobinHood[K, V] {
+	return &RobinHood[K, V]{
+		slots: make([]robinHoodSlot[K, V], minCapacity),
+		mask:  minCapacity - 1,
+		hash:  hash,
+	}
+}
+
+// Len возвращает количество элементов в таблице.
+func (t *RobinHood[K, V]) Len() int {
+	return t.len
+}
+
+// Put записывает значение по ключу.
+// Если элемента с таким ключом нет, то добавляет его.
+func (t *RobinHood[K, V]) Put(key K, value V) {
+	h, i, ok := t.find(key)
+	if ok {
+		t.slots[i].value = value
+		return
+	}
+
+	t.insert(h, key, value)
+}
+
+// Get возвращает значение по ключу и true,
+// если элемент существует.
+func (t *RobinHood[K, V]) Get(key K) (V, bool) {
+	if _, i, ok := t.find(key); ok {
+		return t.slots[i].value, true
+	}
+
+	var zero V
+	return zero, false
+}
+
+// GetOrInsert возвращает значение существующего элемента и true.
+// Если элемента нет, то добавляет переданное значение
+// и возвращает его и false.
+func (t *RobinHood[K, V]) GetOrInsert(key K, value V) (V, bool) {
+	h, i, ok := t.find(key)
+	if ok {
+		return t.slots[i].value, true
+	}
+
+	t.insert(h, key, value)
+
+	return value, false
+}
+
+// Update заменяет значение по ключу результатом fn.
+// Если элемента нет, то fn получает нулевое значение,
+// а результат добавляется в таблицу.
+// Возвращает новое значение.
+func (t *RobinHood[K, V]) Update(key K, fn func(old V) V) V {
+	h, i, ok := t.find(key)
+	if ok {
+		t.slots[i].value = fn(t.slots[i].value)
+		return t.slots[i].value
+	}
+
+	var zero V
+	value := fn(zero)
+	t.insert(h, key, value)
+
+	return value
+}
+
+// Delete удаляет элемент из таблицы.
+// Возвращает значение удаленного элемента и true,
+// если элемент существовал.
+func (t *RobinHood[K, V]) Delete(key K) (V, bool) {
+	_, i, ok := t.find(key)
+	if !ok {
+		var zero V
+		return zero, false
+	}
+
+	value := t.slots[i].value
+
+	// Сдвигаю назад следующие элементы, пока они не в начальной ячейке
+	for j := (i + 1) & t.mask; t.slots[j].dist > 1; j = (j + 1) & t.mask {
+		t.slots[i] = t.slots[j]
+		t.slots[i].dist--
+		i = j
+	}
+
+	t.slots[i] = robinHoodSlot[K, V]{}
+	t.len--
+
+	return value, true
+}
+
+// All возвращает итератор по парам ключ-значение таблицы.
+// Если таблица изменяется во время итерации, то элементы
+// могут быть пропущены или выданы повторно.
+func (t *RobinHood[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for _, s := range t.slots {
+			if s.dist > 0 && !yield(s.key, s.value) {
+				return
+			}
+		}
+	}
+}
+
+// find возвращает перемешанное хэш значение ключа, индекс ячейки с ключом и true.
+// Если элемента нет, то возвращает false.
+func (t *RobinHood[K, V]) find(key K) (uint64, uint64, bool) {
+	h := mix(t.hash.Hash(key))
+
+	i := h & t.mask
+	for dist := uint32(1); ; dist++ {
+		s := &t.slots[i]
+
+		// Если элемент в ячейке ближе к своей начальной ячейке,
+		// чем был бы искомый, то искомого элемента нет
+		if s.dist < dist {
+			return h, i, false
+		}
+
+		if s.hash == h && s.key == key {
+			return h, i, true
+		}
+
+		i = (i + 1) & t.mask
+	}
+}
+
+// insert добавляет элемент, которого нет в таблице.
+// Если таблица заполнена на 7/8, то сначала она увеличивается вдвое.
+func (t *RobinHood[K, V]) insert(h uint64, key K, value V) {
+	if (t.len+1)*8 > len(t.slots)*7 {
+		t.resize(len(t.slots) * 2)
+	}
+
+	t.place(robinHoodSlot[K, V]{key: key, value: value, hash: h, dist: 1})
+	t.len++
+}
+
+// place размещает элемент, вытесняя элементы,
+// которые находятся ближе к своей начальной ячейке.
+func (t *RobinHood[K, V]) place(e robinHoodSlot[K, V]) {
+	for i := e.hash & t.mask; ; i = (i + 1) & t.mask {
+		s := &t.slots[i]
+
+		if s.dist == 0 {
+			*s = e
+			return
+		}
+
+		if s.dist < e.dist {
+			*s, e = e, *s
+		}
+
+		e.dist++
+	}
+}
+
+// resize переносит элементы в массив из n ячеек.
+func (t *RobinHood[K, V]) resize(n int) {
+	slots := t.slots
+
+	t.slots = make([]robinHoodSlot[K, V], n)
+	t.mask = uint64(n - 1)
+
+	for _, s := range slots {
+		if s.dist > 0 {
+			s.dist = 1
+			t.place(s)
+		}
+	}
+}
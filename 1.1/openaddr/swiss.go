@@ -0,0 +1,279 @@
+package openaddr
+
+import (
+	"encoding/binary"
+	"iter"
+	"math/bits"
+
+	"github.com/polRk/data_structures_and_algorithms/1.1/hashtable"
+)
+
+// Управляющие байты ячеек таблицы Swiss.
+// Занятая ячейка хранит младшие 7 бит хэш значения (0..127).
+const (
+	ctrlEmpty   = 0x80 // Свободная ячейка
+	ctrlDeleted = 0xFE // Удаленный элемент
+)
+
+// groupSize задает количество ячеек в группе.
+const groupSize = 8
+
+// Маски младших и старших бит каждого байта группы.
+const (
+	lsbs = 0x0101010101010101
+	msbs = 0x8080808080808080
+)
+
+// Swiss представляет хэш-таблицу с управляющими байтами в стиле SwissTable.
+// Ячейки объединены в группы по 8, и для каждой ячейки хранится байт
+// с младшими 7 битами хэш значения ключа. Поиск сравнивает сразу
+// все 8 управляющих байт группы и проверяет ключ только в ячейках
+// с совпавшими битами.
+type Swiss[K comparable, V any] struct {
+	ctrl   []byte
+	slots  []swissSlot[K, V]
+	groups uint64 // Маска номера группы
+	len    int
+	growth int // Количество свободных ячеек, которые можно занять до роста
+	hash   hashtable.Hasher[K]
+}
+
+// swissSlot представляет ячейку таблицы Swiss.
+type swissSlot[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+// NewSwiss возвращает пустую таблицу с управляющими байтами,
+// использующую переданную хэш функцию.
+func NewSwiss[K comparable, V any](hash hashtable.Hasher[K]) *Swiss[K, V] {
+	t := &Swiss[K, V]{hash: hash}
+	t.init(minCapacity)
+
+	return t
+}
+
+// init заполняет таблицу n свободными ячейками.
+func (t *Swiss[K, V]) init(n int) {
+	t.ctrl = make([]byte, n)
+	for i := range t.ctrl {
+		t.ctrl[i] = ctrlEmpty
+	}
+
+	t.slots = make([]swissSlot[K, V], n)
+	t.groups = uint64(n/groupSize - 1)
+	t.growth = n * 7 / 8
+}
+
+// Len возвращает количество элементов в таблице.
+func (t *Swiss[K, V]) Len() int {
+	return t.len
+}
+
+// Put записывает значение по ключу.
+// Если элемента с таким ключом нет, то добавляет его.
+func (t *Swiss[K, V]) Put(key K, value V) {
+	h, i, ok := t.find(key)
+	if ok {
+		t.slots[i].value = value
+		return
+	}
+
+	t.insert(h, key, value)
+}
+
+// Get возвращает значение по ключу и true,
+// если элемент существует.
+func (t *Swiss[K, V]) Get(key K) (V, bool) {
+	if _, i, ok := t.find(key); ok {
+		return t.slots[i].value, true
+	}
+
+	var zero V
+	return zero, false
+}
+
+// GetOrInsert возвращает значение существующего элемента и true.
+// Если элемента нет, то добавляет переданное значение
+// и возвращает его и false.
+func (t *Swiss[K, V]) GetOrInsert(key K, value V) (V, bool) {
+	h, i, ok := t.find(key)
+	if ok {
+		return t.slots[i].value, true
+	}
+
+	t.insert(h, key, value)
+
+	return value, false
+}
+
+// Update заменяет значение по ключу результатом fn.
+// Если элемента нет, то fn получает нулевое значение,
+// а результат добавляется в таблицу.
+// Возвращает новое значение.
+func (t *Swiss[K, V]) Update(key K, fn func(old V) V) V {
+	h, i, ok := t.find(key)
+	if ok {
+		t.slots[i].value = fn(t.slots[i].value)
+		return t.slots[i].value
+	}
+
+	var zero V
+	value := fn(zero)
+	t.insert(h, key, value)
+
+	return value
+}
+
+// Delete удаляет элемент из таблицы.
+// Возвращает значение удаленного элемента и true,
+// если элемент существовал.
+func (t *Swiss[K, V]) Delete(key K) (V, bool) {
+	_, i, ok := t.find(key)
+	if !ok {
+		var zero V
+		return zero, false
+	}
+
+	value := t.slots[i].value
+
+	// Если в группе есть свободная ячейка, то поиск любого ключа
+	// остановится на этой группе, и ячейку можно сделать свободной.
+	// Иначе оставляю отметку об удалении, чтобы не прервать
+	// пробирование ключей, перешедших в следующие группы
+	if matchEmpty(t.group(i/groupSize)) != 0 {
+		t.ctrl[i] = ctrlEmpty
+		t.growth++
+	} else {
+		t.ctrl[i] = ctrlDeleted
+	}
+
+	t.slots[i] = swissSlot[K, V]{}
+	t.len--
+
+	return value, true
+}
+
+// All возвращает итератор по парам ключ-значение таблицы.
+// Если таблица изменяется во время итерации, то элементы
+// могут быть пропущены или выданы повторно.
+func (t *Swiss[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		ctrl, slots := t.ctrl, t.slots
+
+		for i, c := range ctrl {
+			if c&ctrlEmpty == 0 && !yield(slots[i].key, slots[i].value) {
+				return
+			}
+		}
+	}
+}
+
+// find возвращает перемешанное хэш значение ключа, индекс ячейки с ключом и true.
+// Если элемента нет, то возвращает false.
+func (t *Swiss[K, V]) find(key K) (uint64, uint64, bool) {
+	h := mix(t.hash.Hash(key))
+	h2 := byte(h & 0x7F)
+
+	for g, step := (h>>7)&t.groups, uint64(1); ; g, step = (g+step)&t.groups, step+1 {
+		word := t.group(g)
+
+		// Проверяю ячейки, управляющий байт которых совпал с h2.
+		// Метод сравнения может ошибочно отметить соседнюю ячейку,
+		// поэтому управляющий байт проверяется еще раз
+		for m := matchByte(word, h2); m != 0; m &= m - 1 {
+			i := g*groupSize + uint64(bits.TrailingZeros64(m)/8)
+
+			if t.ctrl[i] == h2 && t.slots[i].key == key {
+				return h, i, true
+			}
+		}
+
+		// Если в группе есть свободная ячейка,
+		// то ключ не мог перейти в следующие группы
+		if matchEmpty(word) != 0 {
+			return h, 0, false
+		}
+	}
+}
+
+// insert добавляет элемент, которого нет в таблице,
+// в первую свободную или удаленную ячейку на пути пробирования.
+func (t *Swiss[K, V]) insert(h uint64, key K, value V) {
+	i := t.free(h)
+
+	// Свободную ячейку можно занять, только если таблица
+	// не заполнена на 7/8, иначе сначала переношу элементы
+	if t.ctrl[i] == ctrlEmpty && t.growth == 0 {
+		t.rehash()
+		i = t.free(h)
+	}
+
+	if t.ctrl[i] == ctrlEmpty {
+		t.growth--
+	}
+
+	t.ctrl[i] = byte(h & 0x7F)
+	t.slots[i] = swissSlot[K, V]{key: key, value: value}
+	t.len++
+}
+
+// free возвращает индекс первой свободной или удаленной ячейки
+// на пути пробирования хэш значения.
+func (t *Swiss[K, V]) free(h uint64) uint64 {
+	for g, step := (h>>7)&t.groups, uint64(1); ; g, step = (g+step)&t.groups, step+1 {
+		if m := matchEmptyOrDeleted(t.group(g)); m != 0 {
+			return g*groupSize + uint64(bits.TrailingZeros64(m)/8)
+		}
+	}
+}
+
+// rehash переносит элементы в новый массив ячеек.
+// Если место занято в основном отметками об удалении,
+// то размер массива не меняется, иначе увеличивается вдвое.
+func (t *Swiss[K, V]) rehash() {
+	ctrl, slots := t.ctrl, t.slots
+
+	n := len(ctrl)
+	if t.len*2 > n*7/8 {
+		n *= 2
+	}
+
+	t.init(n)
+
+	for i, c := range ctrl {
+		if c&ctrlEmpty != 0 {
+			continue
+		}
+
+		h := mix(t.hash.Hash(slots[i].key))
+		j := t.free(h)
+
+		t.ctrl[j] = c
+		t.slots[j] = slots[i]
+		t.growth--
+	}
+}
+
+// group возвращает управляющие байты группы g одним словом.
+func (t *Swiss[K, V]) group(g uint64) uint64 {
+	return binary.LittleEndian.Uint64(t.ctrl[g*groupSize:])
+}
+
+// matchByte возвращает маску байт группы, равных b (старший бит каждого байта).
+// Маска может содержать ложные срабатывания в байтах,
+// следующих за действительно совпавшим.
+func matchByte(word uint64, b byte) uint64 {
+	x := word ^ (lsbs * uint64(b))
+	return (x - lsbs) &^ x & msbs
+}
+
+// matchEmpty возвращает маску свободных ячеек группы.
+func matchEmpty(word uint64) uint64 {
+	return word &^ (word << 6) & msbs
+}
+
+// matchEmptyOrDeleted возвращает маску свободных и удаленных ячеек группы.
+func matchEmptyOrDeleted(word uint64) uint64 {
+	return word &^ (word << 7) & msbs
+}
@@ -0,0 +1,282 @@
+package main
+
+// Converter переводит выражения из инфиксной формы
+// в префиксную и постфиксную и вычисляет их по таблице операторов.
+type Converter struct {
+	ops *OperatorTable
+}
+
+// NewConverter возвращает конвертер с таблицей операторов ops.
+// Если ops равна nil, то используются DefaultOperators.
+func NewConverter(ops *OperatorTable) *Converter {
+	if ops == nil {
+		ops = DefaultOperators()
+	}
+
+	return &Converter{ops: ops}
+}
+
+// defaultConverter используется функциями пакета.
+var defaultConverter = NewConverter(nil)
+
+// Operators возвращает таблицу операторов конвертера.
+func (c *Converter) Operators() *OperatorTable {
+	return c.ops
+}
+
+// Precedence возвращает приоритет бинарного оператора из DefaultOperators,
+// 0 для скобок и -1 для неизвестного символа.
+func Precedence(op string) int {
+	if op == "(" || op == ")" {
+		return 0
+	}
+
+	if o, ok := defaultConverter.ops.Lookup(op, 2); ok {
+		return o.Precedence
+	}
+
+	return -1
+}
+
+// ToPostfix возвращает постфиксную форму выражения с операторами DefaultOperators.
+// Если выражение записано неверно, то возвращает *SyntaxError.
+func ToPostfix(expression string) (Tokens, error) {
+	return defaultConverter.ToPostfix(expression)
+}
+
+// ToPrefix возвращает префиксную форму выражения с операторами DefaultOperators.
+// Если выражение записано неверно, то возвращает *SyntaxError.
+func ToPrefix(expression string) (Tokens, error) {
+	return defaultConverter.ToPrefix(expression)
+}
+
+// ToPostfix возвращает постфиксную форму выражения.
+// Если выражение записано неверно, то возвращает *SyntaxError.
+func (c *Converter) ToPostfix(expression string) (Tokens, error) {
+	tokens, err := c.Tokenize(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.postfix(tokens)
+}
+
+// ToPrefix возвращает префиксную форму выражения.
+// Если выражение записано неверно, то возвращает *SyntaxError.
+func (c *Converter) ToPrefix(expression string) (Tokens, error) {
+	// Обращенное выражение меняет ассоциативность операторов,
+	// поэтому префиксную форму получаю обходом дерева разбора
+	ast, err := c.Parse(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	return ast.Prefix(), nil
+}
+
+// call описывает скобки выражения: вызов функции или группировку.
+type call struct {
+	fn   bool // Скобки вызова функции
+	args int  // Количество аргументов функции
+}
+
+// postfix возвращает лексемы выражения в постфиксном порядке.
+// Операторам и функциям проставляется количество операндов.
+// Если выражение записано неверно, то возвращает *SyntaxError.
+func (c *Converter) postfix(tokens Tokens) (Tokens, error) {
+	var out Tokens
+	s := NewStack[Token]()
+	calls := NewStack[*call]()
+
+	// operand равен true, если следующей лексемой
+	// должен быть операнд: число, переменная, функция,
+	// унарный оператор или открывающая скобка
+	operand := true
+
+	// popOperators забирает из стека операторы, которые выполняются
+	// раньше оператора op: с большим приоритетом, а для левоассоциативного
+	// оператора и с равным, и добавляет их в конец постфиксной формы
+	popOperators := func(op Op) {
+		for !s.Empty() && s.Peek().Kind == Operator {
+			top, _ := c.ops.Lookup(s.Peek().Text, s.Peek().Arity)
+			if top.Precedence < op.Precedence || top.Precedence == op.Precedence && op.Assoc == RightAssoc {
+				break
+			}
+
+			out = append(out, s.Pop())
+		}
+	}
+
+	// Прохожусь по всем лексемам выражения
+	for i, t := range tokens {
+		switch t.Kind {
+		// Если число или переменная,
+		// то добавляю в конец постфиксной формы
+		case Number, Identifier:
+			if !operand {
+				return nil, expected(t, operand)
+			}
+
+			out = append(out, t)
+			operand = false
+
+		// Если функция, то добавляю ее в стек
+		// до закрывающей скобки ее аргументов
+		case Function:
+			if !operand {
+				return nil, expected(t, operand)
+			}
+
+			if _, ok := c.ops.LookupFunc(t.Text); !ok {
+				return nil, syntaxError(t.Pos, "неизвестная функция %q", t.Text)
+			}
+
+			s.Push(t)
+
+		// Если скобка открывающая, то добавляю ее в стек
+		// и начинаю считать аргументы, если это вызов функции
+		case LeftParen:
+			if !operand {
+				return nil, expected(t, operand)
+			}
+
+			s.Push(t)
+			calls.Push(&call{fn: i > 0 && tokens[i-1].Kind == Function, args: 1})
+
+		// Если запятая, то забираю из стека все операторы
+		// аргумента функции и считаю следующий аргумент
+		case Comma:
+			if operand {
+				return nil, expected(t, operand)
+			}
+
+			for !s.Empty() && s.Peek().Kind != LeftParen {
+				out = append(out, s.Pop())
+			}
+
+			if s.Empty() || !calls.Peek().fn {
+				return nil, syntaxError(t.Pos, "запятая вне вызова функции")
+			}
+
+			calls.Peek().args++
+			operand = true
+
+		// Если скобка закрывающая, то забираю из
+		// стека все лексемы до открывающей скобки
+		// и добавляю их в конец постфиксной формы,
+		// удаляю открывающую скобку из стека
+		case RightParen:
+			if operand {
+				empty := i > 0 && tokens[i-1].Kind == LeftParen
+
+				switch {
+				// Функцию можно вызвать без аргументов
+				case empty && calls.Peek().fn:
+					calls.Peek().args = 0
+				case empty:
+					return nil, syntaxError(tokens[i-1].Pos, "пустые скобки")
+				default:
+					return nil, expected(t, operand)
+				}
+			}
+
+			for !s.Empty() && s.Peek().Kind != LeftParen {
+				out = append(out, s.Pop())
+			}
+
+			if s.Empty() {
+				return nil, syntaxError(t.Pos, "нет открывающей скобки")
+			}
+
+			// Удаляю открывающую скобку
+			s.Pop()
+
+			// После аргументов функции добавляю ее
+			// в конец постфиксной формы
+			if cl := calls.Pop(); cl.fn {
+				fn := s.Pop()
+				if err := c.checkArity(fn, cl.args); err != nil {
+					return nil, err
+				}
+
+				fn.Arity = cl.args
+				out = append(out, fn)
+			}
+
+			operand = false
+
+		// Унарный оператор перед операндом добавляю в стек,
+		// а после операнда сразу в конец постфиксной формы.
+		// Бинарный оператор добавляю в стек
+		default:
+			if operand {
+				op, ok := c.ops.Lookup(t.Text, 1)
+				if !ok || op.Postfix {
+					return nil, expected(t, operand)
+				}
+
+				t.Arity = 1
+				s.Push(t)
+
+				continue
+			}
+
+			if op, ok := c.ops.Lookup(t.Text, 1); ok && op.Postfix {
+				popOperators(op)
+
+				t.Arity = 1
+				out = append(out, t)
+
+				continue
+			}
+
+			op, ok := c.ops.Lookup(t.Text, 2)
+			if !ok {
+				return nil, syntaxError(t.Pos, "оператор %q записывается перед операндом", t.Text)
+			}
+
+			popOperators(op)
+
+			// Добавляю оператор в стек
+			t.Arity = 2
+			s.Push(t)
+			operand = true
+		}
+	}
+
+	if len(tokens) == 0 {
+		return nil, syntaxError(0, "пустое выражение")
+	}
+
+	if operand {
+		last := tokens[len(tokens)-1]
+		return nil, syntaxError(last.Pos+len([]rune(last.Text)), "выражение не закончено")
+	}
+
+	// Если стек не пуст, то забираю все лексемы из стека
+	// и добавляю их в конец постфиксной формы
+	for !s.Empty() {
+		t := s.Pop()
+		if t.Kind == LeftParen {
+			return nil, syntaxError(t.Pos, "нет закрывающей скобки")
+		}
+
+		out = append(out, t)
+	}
+
+	return out, nil
+}
+
+// checkArity проверяет количество аргументов функции.
+func (c *Converter) checkArity(fn Token, args int) error {
+	f, _ := c.ops.LookupFunc(fn.Text)
+
+	switch {
+	case f.Arity < 0 && args == 0:
+		return syntaxError(fn.Pos, "функции %s нужен хотя бы один аргумент", fn.Text)
+	case f.Arity >= 0 && args != f.Arity:
+		return syntaxError(fn.Pos, "функции %s нужно аргументов: %d, передано: %d", fn.Text, f.Arity, args)
+	}
+
+	return nil
+}
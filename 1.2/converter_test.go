@@ -0,0 +1,241 @@
+package main
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+// vars содержит значения переменных выражений теста.
+var vars = map[string]float64{"a": 2, "b": 3, "c": 2}
+
+// checkConvert проверяет префиксную и постфиксную формы выражения
+// и значения обеих форм.
+func checkConvert(t *testing.T, c *Converter, expression, prefix, postfix string, value float64) {
+	t.Helper()
+
+	post, err := c.ToPostfix(expression)
+	if err != nil {
+		t.Fatalf("ToPostfix(%q): %v", expression, err)
+	}
+
+	pre, err := c.ToPrefix(expression)
+	if err != nil {
+		t.Fatalf("ToPrefix(%q): %v", expression, err)
+	}
+
+	if post.String() != postfix {
+		t.Errorf("ToPostfix(%q) = %q, want %q", expression, post, postfix)
+	}
+
+	if pre.String() != prefix {
+		t.Errorf("ToPrefix(%q) = %q, want %q", expression, pre, prefix)
+	}
+
+	if got, err := c.EvalPostfix(post, vars); err != nil || got != value {
+		t.Errorf("EvalPostfix(%q) = %v, %v, want %v", postfix, got, err, value)
+	}
+
+	if got, err := c.EvalPrefix(pre, vars); err != nil || got != value {
+		t.Errorf("EvalPrefix(%q) = %v, %v, want %v", prefix, got, err, value)
+	}
+}
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		expression string
+		prefix     string
+		postfix    string
+		value      float64
+	}{
+		{"a+b", "+ a b", "a b +", 5},
+		{"1 + 2 * 3", "+ 1 * 2 3", "1 2 3 * +", 7},
+		{"(1 + 2) * 3", "* + 1 2 3", "1 2 + 3 *", 9},
+		// Левоассоциативный оператор выполняется слева направо,
+		// а степень справа налево
+		{"a-b-c", "- - a b c", "a b - c -", -3},
+		{"a^b^c", "^ a ^ b c", "a b c ^ ^", 512},
+		{"(a^b)^c", "^ ^ a b c", "a b ^ c ^", 64},
+		{"24 / 4 / 2", "/ / 24 4 2", "24 4 / 2 /", 3},
+		// Многозначные числа, дробная часть и порядок
+		{"12.5 / 0.5 - 10", "- / 12.5 0.5 10", "12.5 0.5 / 10 -", 15},
+		{"1e3 + .5", "+ 1e3 .5", "1e3 .5 +", 1000.5},
+		// Унарный минус выполняется после степени
+		{"-a^2", "-@1 ^ a 2", "a 2 ^ -@1", -4},
+		{"2 * -b", "* 2 -@1 b", "2 b -@1 *", -6},
+		{"--a", "-@1 -@1 a", "a -@1 -@1", 2},
+		{"3! + 1", "+ !@1 3 1", "3 !@1 1 +", 7},
+		{"-b!", "-@1 !@1 b", "b !@1 -@1", -6},
+		{"max(a, b, c) * 2", "* max@3 a b c 2", "a b c max@3 2 *", 6},
+		{"sqrt(16) + min(a + b, 4)", "+ sqrt@1 16 min@2 + a b 4", "16 sqrt@1 a b + 4 min@2 +", 8},
+		{"abs(-(a - b))", "abs@1 -@1 - a b", "a b - -@1 abs@1", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			checkConvert(t, defaultConverter, tt.expression, tt.prefix, tt.postfix, tt.value)
+		})
+	}
+}
+
+func TestConvertCustomOperators(t *testing.T) {
+	ops := DefaultOperators()
+
+	err := errors.Join(
+		ops.Add(Op{Symbol: "%", Precedence: 2, Assoc: LeftAssoc, Arity: 2, Apply: func(a ...float64) (float64, error) {
+			if a[1] == 0 {
+				return 0, ErrDivisionByZero
+			}
+
+			return math.Mod(a[0], a[1]), nil
+		}}),
+		// Символ "//" длиннее "/", поэтому он выбирается первым
+		ops.Add(Op{Symbol: "//", Precedence: 2, Assoc: LeftAssoc, Arity: 2, Apply: func(a ...float64) (float64, error) {
+			if a[1] == 0 {
+				return 0, ErrDivisionByZero
+			}
+
+			return math.Floor(a[0] / a[1]), nil
+		}}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := NewConverter(ops)
+
+	tests := []struct {
+		expression string
+		prefix     string
+		postfix    string
+		value      float64
+	}{
+		{"7 // 2", "// 7 2", "7 2 //", 3},
+		{"7 // 2 % 2", "% // 7 2 2", "7 2 // 2 %", 1},
+		{"17 % 5 * 2", "* % 17 5 2", "17 5 % 2 *", 4},
+		{"9 // 2 / 2", "/ // 9 2 2", "9 2 // 2 /", 2},
+		{"9 / 2 // 2", "// / 9 2 2", "9 2 / 2 //", 2},
+		{"1 + 7 // b ^ 2", "+ 1 // 7 ^ b 2", "1 7 b 2 ^ // +", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			checkConvert(t, c, tt.expression, tt.prefix, tt.postfix, tt.value)
+		})
+	}
+
+	// Операторы конвертера не попадают в таблицу по умолчанию
+	if _, err := ToPostfix("7 % 2"); err == nil {
+		t.Error(`ToPostfix("7 % 2") без ошибки с операторами по умолчанию`)
+	}
+}
+
+func TestSyntaxError(t *testing.T) {
+	tests := []struct {
+		expression string
+		pos        int
+	}{
+		{"", 0},
+		{"   ", 0},
+		{"1 +", 3},
+		{"1 + * 2", 4},
+		{"2 3", 2},
+		{"(1 + 2", 0},
+		{"1 + (2 * (3 - 1)", 4},
+		{"1 + 2)", 5},
+		{"()", 0},
+		{"1 $ 2", 2},
+		// Позиции считаются в символах, а не в байтах
+		{"ёж + 1 $", 7},
+		{"!3", 0},
+		{"1, 2", 1},
+		{"foo(1)", 0},
+		{"sin(1, 2)", 0},
+		{"max()", 0},
+		{"min(1,)", 6},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			_, err := ToPostfix(tt.expression)
+
+			var se *SyntaxError
+			if !errors.As(err, &se) {
+				t.Fatalf("ToPostfix(%q) = %v, want *SyntaxError", tt.expression, err)
+			}
+
+			if se.Pos != tt.pos {
+				t.Errorf("ToPostfix(%q): позиция %d (%v), want %d", tt.expression, se.Pos, se, tt.pos)
+			}
+
+			// Префиксная форма находит ту же ошибку
+			if _, err := ToPrefix(tt.expression); !errors.As(err, &se) || se.Pos != tt.pos {
+				t.Errorf("ToPrefix(%q) = %v, want позиция %d", tt.expression, err, tt.pos)
+			}
+		})
+	}
+}
+
+func TestSyntaxErrorCaret(t *testing.T) {
+	err := &SyntaxError{Pos: 4, Msg: "ошибка"}
+
+	if got, want := err.Caret("1 +\t$ 2"), "1 +\t$ 2\n   \t^"; got != want {
+		t.Errorf("Caret() = %q, want %q", got, want)
+	}
+}
+
+func TestEvalError(t *testing.T) {
+	tests := []struct {
+		expression string
+		err        error
+		token      string
+	}{
+		{"1 / (a - c)", ErrDivisionByZero, "/"},
+		{"x + 1", ErrUndefined, "x"},
+		{"(-1)!", ErrDomain, "!"},
+		{"2.5!", ErrDomain, "!"},
+		{"1 + 2 * y", ErrUndefined, "y"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			postfix, err := ToPostfix(tt.expression)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			prefix, err := ToPrefix(tt.expression)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, errPost := EvalPostfix(postfix, vars)
+			_, errPre := EvalPrefix(prefix, vars)
+
+			for _, err := range []error{errPost, errPre} {
+				var ee *EvalError
+				if !errors.Is(err, tt.err) || !errors.As(err, &ee) || ee.Token.Text != tt.token {
+					t.Errorf("ошибка %v, want %v в лексеме %q", err, tt.err, tt.token)
+				}
+			}
+		})
+	}
+}
+
+func TestEvalMalformed(t *testing.T) {
+	// Лексемы в неверном порядке не образуют выражения
+	tokens := Tokens{
+		{Kind: Number, Text: "1"},
+		{Kind: Operator, Text: "+", Arity: 2},
+	}
+
+	if _, err := EvalPostfix(tokens, nil); !errors.Is(err, ErrMalformed) {
+		t.Errorf("EvalPostfix(%q) = %v, want %v", tokens, err, ErrMalformed)
+	}
+
+	tokens = Tokens{{Kind: Number, Text: "1"}, {Kind: Number, Text: "2"}}
+
+	if _, err := EvalPostfix(tokens, nil); !errors.Is(err, ErrMalformed) {
+		t.Errorf("EvalPostfix(%q) = %v, want %v", tokens, err, ErrMalformed)
+	}
+}
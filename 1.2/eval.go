@@ -0,0 +1,180 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Ошибки вычисления выражения.
+var (
+	ErrDivisionByZero = errors.New("деление на ноль")
+	ErrUndefined      = errors.New("переменная не определена")
+	ErrMalformed      = errors.New("неверная запись выражения")
+	ErrUnsupported    = errors.New("оператор или функцию нельзя вычислить")
+	ErrDomain         = errors.New("значение вне области определения")
+)
+
+// EvalError представляет ошибку вычисления лексемы выражения.
+// Err содержит одну из ошибок ErrDivisionByZero, ErrUndefined, ErrMalformed,
+// ErrUnsupported, ErrDomain или ошибку функции Apply оператора или функции.
+type EvalError struct {
+	Token Token
+	Err   error
+}
+
+// Error возвращает описание ошибки с лексемой и ее позицией.
+func (e *EvalError) Error() string {
+	return fmt.Sprintf("%s: %q в позиции %d", e.Err, e.Token.Text, e.Token.Pos+1)
+}
+
+// Unwrap возвращает причину ошибки.
+func (e *EvalError) Unwrap() error {
+	return e.Err
+}
+
+// EvalPostfix вычисляет выражение в постфиксной форме с операторами DefaultOperators.
+// Значения переменных берутся из vars.
+func EvalPostfix(postfix Tokens, vars map[string]float64) (float64, error) {
+	return defaultConverter.EvalPostfix(postfix, vars)
+}
+
+// EvalPrefix вычисляет выражение в префиксной форме с операторами DefaultOperators.
+// Значения переменных берутся из vars.
+func EvalPrefix(prefix Tokens, vars map[string]float64) (float64, error) {
+	return defaultConverter.EvalPrefix(prefix, vars)
+}
+
+// EvalPostfix вычисляет выражение в постфиксной форме.
+// Значения переменных берутся из vars.
+func (c *Converter) EvalPostfix(postfix Tokens, vars map[string]float64) (float64, error) {
+	return c.eval(postfix, vars, false)
+}
+
+// EvalPrefix вычисляет выражение в префиксной форме.
+// Значения переменных берутся из vars.
+func (c *Converter) EvalPrefix(prefix Tokens, vars map[string]float64) (float64, error) {
+	// Префиксная форма вычисляется как постфиксная,
+	// если читать ее справа налево и брать операнды в обратном порядке
+	reversed := make(Tokens, len(prefix))
+	for i, t := range prefix {
+		reversed[len(prefix)-1-i] = t
+	}
+
+	return c.eval(reversed, vars, true)
+}
+
+// eval вычисляет выражение, записанное лексемами в постфиксном порядке.
+// Если prefix равен true, то первым операндом считается верхнее значение стека.
+func (c *Converter) eval(tokens Tokens, vars map[string]float64, prefix bool) (float64, error) {
+	s := NewStack[float64]()
+
+	// Прохожусь по всем лексемам, числа и значения переменных
+	// добавляю в стек, а для оператора и функции забираю операнды из стека
+	// и добавляю в стек результат
+	for _, t := range tokens {
+		switch t.Kind {
+		case Number:
+			v, err := strconv.ParseFloat(t.Text, 64)
+			if err != nil {
+				return 0, &EvalError{Token: t, Err: ErrMalformed}
+			}
+
+			s.Push(v)
+		case Identifier:
+			v, ok := vars[t.Text]
+			if !ok {
+				return 0, &EvalError{Token: t, Err: ErrUndefined}
+			}
+
+			s.Push(v)
+		case Operator, Function:
+			var apply func(...float64) (float64, error)
+
+			if t.Kind == Function {
+				f, ok := c.ops.LookupFunc(t.Text)
+				if !ok || f.Arity >= 0 && f.Arity != t.Arity {
+					return 0, &EvalError{Token: t, Err: ErrMalformed}
+				}
+
+				apply = f.Apply
+			} else {
+				op, ok := c.ops.Lookup(t.Text, t.Arity)
+				if !ok {
+					return 0, &EvalError{Token: t, Err: ErrMalformed}
+				}
+
+				apply = op.Apply
+			}
+
+			if apply == nil {
+				return 0, &EvalError{Token: t, Err: ErrUnsupported}
+			}
+
+			args := make([]float64, t.Arity)
+			for i := range args {
+				if s.Empty() {
+					return 0, &EvalError{Token: t, Err: ErrMalformed}
+				}
+
+				args[i] = s.Pop()
+			}
+
+			// Из стека операнды постфиксной формы достаются
+			// справа налево, а префиксной слева направо
+			if !prefix {
+				slices.Reverse(args)
+			}
+
+			v, err := apply(args...)
+			if err != nil {
+				return 0, &EvalError{Token: t, Err: err}
+			}
+
+			s.Push(v)
+		default:
+			return 0, &EvalError{Token: t, Err: ErrMalformed}
+		}
+	}
+
+	// В стеке должно остаться ровно одно значение
+	if s.Empty() {
+		return 0, ErrMalformed
+	}
+
+	v := s.Pop()
+
+	if !s.Empty() {
+		return 0, ErrMalformed
+	}
+
+	return v, nil
+}
+
+// ParseVars разбирает значения переменных вида "x=1 y=2.5".
+// Значения можно разделять пробелами или запятыми.
+func ParseVars(line string) (map[string]float64, error) {
+	vars := make(map[string]float64)
+
+	fields := strings.FieldsFunc(line, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t'
+	})
+
+	for _, field := range fields {
+		name, value, ok := strings.Cut(field, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("ожидается переменная=значение: %q", field)
+		}
+
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("неверное значение переменной %s: %q", name, value)
+		}
+
+		vars[name] = v
+	}
+
+	return vars, nil
+}
@@ -2,41 +2,44 @@ package main
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"os"
-	"unicode"
+	"slices"
 )
 
 // Stack представляет список элементов,
 // организованных по принципу LIFO
-type Stack struct {
-	top *Node
+type Stack[T any] struct {
+	top *Node[T]
 }
 
 // Node представляет элемента стека
-type Node struct {
-	value rune
-	next  *Node // top(*Node) -> *Node -> *Node -> nil
+type Node[T any] struct {
+	value T
+	next  *Node[T] // top(*Node) -> *Node -> *Node -> nil
 }
 
 // NewStack возвращает новый стек.
-func NewStack() *Stack {
-	return &Stack{nil}
+func NewStack[T any]() *Stack[T] {
+	return &Stack[T]{nil}
 }
 
 // Peek возвращает значение, при этом не удаляет его.
-func (s *Stack) Peek() rune {
+func (s *Stack[T]) Peek() T {
 	if s.top == nil {
-		return 0
+		var zero T
+		return zero
 	}
 
 	return s.top.value
 }
 
 // Pop удаляет и возвращает значение.
-func (s *Stack) Pop() rune {
+func (s *Stack[T]) Pop() T {
 	if s.top == nil {
-		return 0
+		var zero T
+		return zero
 	}
 
 	n := s.top
@@ -45,119 +48,25 @@ func (s *Stack) Pop() rune {
 }
 
 // Push добавляет значение на верх стека.
-func (s *Stack) Push(value rune) {
-	n := &Node{value: value, next: s.top}
+func (s *Stack[T]) Push(value T) {
+	n := &Node[T]{value: value, next: s.top}
 	s.top = n
 }
 
-// Reverse возвращает обращенную строку.
-func Reverse(in string) (out string) {
-	for _, r := range in {
-		if r == '(' {
-			out = ")" + out
-		} else if r == ')' {
-			out = "(" + out
-		} else {
-			out = string(r) + out
-		}
-	}
-
-	return out
-}
-
-// Precedence возвращает приоритет функции.
-func Precedence(char int32) int {
-	switch char {
-	case '(', ')':
-		return 0
-	case '+', '-':
-		return 1
-	case '*', '/':
-		return 2
-	case '^':
-		return 3
-	}
-
-	return -1
+// Empty возвращает true, если стек пуст.
+func (s *Stack[T]) Empty() bool {
+	return s.top == nil
 }
 
-// ToPostfix возвращает постфиксную форму выражения.
-func ToPostfix(expression string) string {
-	var postfix string
-	s := NewStack()
-
-	// Прохожусь по всем символам переданной строки
-	for _, char := range expression {
-		// Если пробел, то пропускаю
-		if unicode.IsSpace(char) {
-			continue
-		}
-
-		// Если буква или число,
-		// то добавляю в конец постфиксной строки
-		if unicode.IsLetter(char) || unicode.IsNumber(char) {
-			postfix += string(char)
-			continue
-		}
+// printError выводит ошибку, а для ошибки в записи выражения
+// еще и выражение со стрелкой на символ с ошибкой.
+func printError(expression string, err error) {
+	fmt.Println("Ошибка:", err)
 
-		// Если скобка открывающая или возведение в степень,
-		// то добавляю символ в стек
-		if char == '(' || char == '^' {
-			s.Push(char)
-			continue
-		}
-
-		// Если скобка закрывающая, то забираю из
-		// стека все символы до открывающей скобки
-		// и добавляю их в конец постфиксной строки,
-		// удаляю открывающую скобку из стека
-		if char == ')' {
-			for s.top != nil && s.Peek() != '(' {
-				r := s.Pop()
-				postfix += string(r)
-			}
-
-			// Удаляю открывающую скобку
-			s.Pop()
-			continue
-		}
-
-		// Всех остальные символы, чей приоритет больше или равен
-		// приоритету символа, забираю из стека
-		// и добавляю в конец постфиксной строки
-		for s.top != nil && Precedence(char) <= Precedence(s.Peek()) {
-			r := s.Pop()
-			postfix += string(r)
-		}
-
-		// Добавляю символ в стек
-		s.Push(char)
+	var syntax *SyntaxError
+	if errors.As(err, &syntax) {
+		fmt.Println(syntax.Caret(expression))
 	}
-
-	// Если стек не пуст, то забираю все символы из стека
-	// и добавляю их в конец постфиксной строку
-	for s.top != nil {
-		r := s.Pop()
-		postfix += string(r)
-	}
-
-	return postfix
-}
-
-// ToPrefix возвращает префиксную форму выражения.
-func ToPrefix(expression string) string {
-	var prefix string
-
-	postfix := ToPostfix(Reverse(expression))
-
-	// Воспользуюсь алгоритмом постфиксной трансляции.
-	// Полученную строку записываю справа налево
-	// в префиксную строку
-	for _, char := range postfix {
-		prefix = string(char) + prefix
-	}
-
-	return prefix
 }
 
 func main() {
@@ -175,9 +84,44 @@ func main() {
 		return
 	}
 
-	prefix := ToPrefix(string(expression))
-	postfix := ToPostfix(string(expression))
+	prefix, err := ToPrefix(string(expression))
+	if err != nil {
+		printError(string(expression), err)
+		return
+	}
+
+	postfix, err := ToPostfix(string(expression))
+	if err != nil {
+		printError(string(expression), err)
+		return
+	}
 
 	fmt.Println("Выражение в префиксной форме:", prefix)
 	fmt.Println("Выражение в постфиксной форме:", postfix)
+
+	vars := map[string]float64{}
+
+	// Если в выражении есть переменные, то спрашиваю их значения
+	if slices.ContainsFunc(postfix, func(t Token) bool { return t.Kind == Identifier }) {
+		fmt.Print("Введите значения переменных (x=1 y=2): ")
+
+		line, _, err := in.ReadLine()
+		if err != nil {
+			fmt.Println("Error: ", err)
+			return
+		}
+
+		if vars, err = ParseVars(string(line)); err != nil {
+			fmt.Println("Ошибка:", err)
+			return
+		}
+	}
+
+	value, err := EvalPostfix(postfix, vars)
+	if err != nil {
+		fmt.Println("Ошибка:", err)
+		return
+	}
+
+	fmt.Println("Значение выражения:", value)
 }
@@ -0,0 +1,247 @@
+package main
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Assoc представляет ассоциативность оператора.
+type Assoc int
+
+// Виды ассоциативности.
+const (
+	LeftAssoc  Assoc = iota // a-b-c = (a-b)-c
+	RightAssoc              // a^b^c = a^(b^c)
+)
+
+// Op описывает оператор выражения.
+type Op struct {
+	Symbol     string
+	Precedence int // Больше 0, чем больше, тем раньше выполняется
+	Assoc      Assoc
+	Arity      int  // Количество операндов: 1 или 2
+	Postfix    bool // Унарный оператор записывается после операнда, как a!
+
+	// Apply вычисляет оператор, операнды передаются слева направо.
+	// Если Apply не задана, то выражение с оператором нельзя вычислить
+	Apply func(args ...float64) (float64, error)
+}
+
+// Func описывает функцию выражения, например max(a, b, c).
+type Func struct {
+	Name  string
+	Arity int // Количество аргументов, -1 для любого количества от 1
+
+	// Apply вычисляет функцию, аргументы передаются слева направо
+	Apply func(args ...float64) (float64, error)
+}
+
+// OperatorTable представляет набор операторов и функций выражения.
+// Унарный и бинарный операторы могут иметь один символ, как минус.
+type OperatorTable struct {
+	binary  map[string]Op
+	unary   map[string]Op
+	funcs   map[string]Func
+	symbols []string // Символы операторов от длинных к коротким
+}
+
+// NewOperatorTable возвращает таблицу с операторами ops без функций.
+func NewOperatorTable(ops ...Op) (*OperatorTable, error) {
+	t := &OperatorTable{
+		binary: make(map[string]Op),
+		unary:  make(map[string]Op),
+		funcs:  make(map[string]Func),
+	}
+
+	for _, op := range ops {
+		if err := t.Add(op); err != nil {
+			return nil, err
+		}
+	}
+
+	return t, nil
+}
+
+// DefaultOperators возвращает таблицу с бинарными операторами + - * / ^,
+// унарными + и -, факториалом ! и функциями sin, cos, tan, sqrt, abs,
+// exp, ln, min и max.
+func DefaultOperators() *OperatorTable {
+	t, _ := NewOperatorTable(
+		Op{Symbol: "+", Precedence: 1, Assoc: LeftAssoc, Arity: 2, Apply: func(a ...float64) (float64, error) {
+			return a[0] + a[1], nil
+		}},
+		Op{Symbol: "-", Precedence: 1, Assoc: LeftAssoc, Arity: 2, Apply: func(a ...float64) (float64, error) {
+			return a[0] - a[1], nil
+		}},
+		Op{Symbol: "*", Precedence: 2, Assoc: LeftAssoc, Arity: 2, Apply: func(a ...float64) (float64, error) {
+			return a[0] * a[1], nil
+		}},
+		Op{Symbol: "/", Precedence: 2, Assoc: LeftAssoc, Arity: 2, Apply: func(a ...float64) (float64, error) {
+			if a[1] == 0 {
+				return 0, ErrDivisionByZero
+			}
+
+			return a[0] / a[1], nil
+		}},
+		// Унарный минус выполняется после степени: -a^2 = -(a^2)
+		Op{Symbol: "+", Precedence: 3, Assoc: RightAssoc, Arity: 1, Apply: func(a ...float64) (float64, error) {
+			return a[0], nil
+		}},
+		Op{Symbol: "-", Precedence: 3, Assoc: RightAssoc, Arity: 1, Apply: func(a ...float64) (float64, error) {
+			return -a[0], nil
+		}},
+		Op{Symbol: "^", Precedence: 4, Assoc: RightAssoc, Arity: 2, Apply: func(a ...float64) (float64, error) {
+			return math.Pow(a[0], a[1]), nil
+		}},
+		Op{Symbol: "!", Precedence: 5, Assoc: LeftAssoc, Arity: 1, Postfix: true, Apply: factorial},
+	)
+
+	unary := func(fn func(float64) float64) func(...float64) (float64, error) {
+		return func(a ...float64) (float64, error) { return fn(a[0]), nil }
+	}
+
+	for _, f := range []Func{
+		{Name: "sin", Arity: 1, Apply: unary(math.Sin)},
+		{Name: "cos", Arity: 1, Apply: unary(math.Cos)},
+		{Name: "tan", Arity: 1, Apply: unary(math.Tan)},
+		{Name: "sqrt", Arity: 1, Apply: unary(math.Sqrt)},
+		{Name: "abs", Arity: 1, Apply: unary(math.Abs)},
+		{Name: "exp", Arity: 1, Apply: unary(math.Exp)},
+		{Name: "ln", Arity: 1, Apply: unary(math.Log)},
+		{Name: "min", Arity: -1, Apply: func(a ...float64) (float64, error) { return slices.Min(a), nil }},
+		{Name: "max", Arity: -1, Apply: func(a ...float64) (float64, error) { return slices.Max(a), nil }},
+	} {
+		t.AddFunc(f)
+	}
+
+	return t
+}
+
+// factorial возвращает факториал целого неотрицательного числа.
+func factorial(a ...float64) (float64, error) {
+	if a[0] < 0 || a[0] != math.Trunc(a[0]) {
+		return 0, ErrDomain
+	}
+
+	return math.Gamma(a[0] + 1), nil
+}
+
+// Add добавляет оператор в таблицу или заменяет оператор
+// с тем же символом и количеством операндов.
+// Символ оператора не может начинаться с буквы, цифры или скобки
+// и не может содержать пробелы и запятые.
+func (t *OperatorTable) Add(op Op) error {
+	if op.Symbol == "" {
+		return errors.New("пустой символ оператора")
+	}
+
+	for i, r := range op.Symbol {
+		if unicode.IsSpace(r) || r == '(' || r == ')' || r == ',' ||
+			i == 0 && (unicode.IsLetter(r) || unicode.IsNumber(r) || r == '_') {
+			return fmt.Errorf("недопустимый символ оператора: %q", op.Symbol)
+		}
+	}
+
+	if op.Precedence <= 0 {
+		return fmt.Errorf("приоритет оператора %s должен быть больше 0", op.Symbol)
+	}
+
+	if op.Assoc != LeftAssoc && op.Assoc != RightAssoc {
+		return fmt.Errorf("неизвестная ассоциативность оператора %s", op.Symbol)
+	}
+
+	var ops map[string]Op
+
+	switch op.Arity {
+	case 1:
+		ops = t.unary
+	case 2:
+		ops = t.binary
+	default:
+		return fmt.Errorf("оператор %s: поддерживаются только унарные и бинарные операторы", op.Symbol)
+	}
+
+	if op.Postfix && op.Arity != 1 {
+		return fmt.Errorf("оператор %s: после операнда записываются только унарные операторы", op.Symbol)
+	}
+
+	// После операнда бинарный оператор нельзя было бы отличить
+	// от унарного с тем же символом
+	_, binary := t.binary[op.Symbol]
+	if u, ok := t.unary[op.Symbol]; op.Postfix && binary || op.Arity == 2 && ok && u.Postfix {
+		return fmt.Errorf("оператор %s не может быть бинарным и записываться после операнда", op.Symbol)
+	}
+
+	if _, unary := t.unary[op.Symbol]; !binary && !unary {
+		t.symbols = append(t.symbols, op.Symbol)
+
+		// Длинные символы проверяю первыми, чтобы // не читался как два /
+		slices.SortStableFunc(t.symbols, func(a, b string) int {
+			return cmp.Compare(utf8.RuneCountInString(b), utf8.RuneCountInString(a))
+		})
+	}
+
+	ops[op.Symbol] = op
+
+	return nil
+}
+
+// AddFunc добавляет функцию в таблицу или заменяет функцию с тем же именем.
+func (t *OperatorTable) AddFunc(f Func) error {
+	if f.Name == "" {
+		return errors.New("пустое имя функции")
+	}
+
+	for i, r := range f.Name {
+		if !(unicode.IsLetter(r) || r == '_' || i > 0 && unicode.IsNumber(r)) {
+			return fmt.Errorf("недопустимое имя функции: %q", f.Name)
+		}
+	}
+
+	if f.Arity < -1 {
+		return fmt.Errorf("функция %s: количество аргументов должно быть не меньше 0 или -1", f.Name)
+	}
+
+	t.funcs[f.Name] = f
+
+	return nil
+}
+
+// Lookup возвращает оператор с символом symbol и количеством операндов arity.
+func (t *OperatorTable) Lookup(symbol string, arity int) (Op, bool) {
+	var op Op
+	var ok bool
+
+	switch arity {
+	case 1:
+		op, ok = t.unary[symbol]
+	case 2:
+		op, ok = t.binary[symbol]
+	}
+
+	return op, ok
+}
+
+// LookupFunc возвращает функцию с именем name.
+func (t *OperatorTable) LookupFunc(name string) (Func, bool) {
+	f, ok := t.funcs[name]
+	return f, ok
+}
+
+// match возвращает символ оператора, с которого начинается s,
+// или пустую строку.
+func (t *OperatorTable) match(s []rune) string {
+	for _, symbol := range t.symbols {
+		n := utf8.RuneCountInString(symbol)
+		if len(s) >= n && string(s[:n]) == symbol {
+			return symbol
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SyntaxError представляет ошибку в записи выражения.
+type SyntaxError struct {
+	Pos int // Номер символа с ошибкой, начиная с 0
+	Msg string
+}
+
+// Error возвращает описание ошибки со столбцом, начиная с 1.
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("столбец %d: %s", e.Pos+1, e.Msg)
+}
+
+// Caret возвращает выражение и строку под ним
+// со стрелкой на символ с ошибкой.
+func (e *SyntaxError) Caret(expression string) string {
+	// Табуляцию сохраняю, чтобы стрелка не сдвигалась
+	var pad strings.Builder
+
+	for i, r := range []rune(expression) {
+		if i == e.Pos {
+			break
+		}
+
+		if r == '\t' {
+			pad.WriteRune('\t')
+		} else {
+			pad.WriteRune(' ')
+		}
+	}
+
+	return expression + "\n" + pad.String() + "^"
+}
+
+// syntaxError возвращает ошибку в символе pos.
+func syntaxError(pos int, format string, args ...any) *SyntaxError {
+	return &SyntaxError{Pos: pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+// expected возвращает ошибку неожиданной лексемы.
+// Если operand равен true, то ожидался операнд, иначе оператор.
+func expected(t Token, operand bool) *SyntaxError {
+	if operand {
+		return syntaxError(t.Pos, "ожидается число, переменная или открывающая скобка вместо %q", t.Text)
+	}
+
+	return syntaxError(t.Pos, "ожидается оператор вместо %q", t.Text)
+}
+
+// AST представляет дерево разбора выражения.
+// Лист дерева содержит число или переменную,
+// а узел — оператор или функцию и их операнды.
+type AST struct {
+	Token Token
+	Args  []AST // Операнды оператора слева направо
+}
+
+// Parse разбирает выражение в инфиксной форме с операторами DefaultOperators.
+// Если выражение записано неверно, то возвращает *SyntaxError
+// с номером символа, в котором найдена ошибка.
+func Parse(expression string) (AST, error) {
+	return defaultConverter.Parse(expression)
+}
+
+// Parse разбирает выражение в инфиксной форме.
+// Если выражение записано неверно, то возвращает *SyntaxError
+// с номером символа, в котором найдена ошибка.
+func (c *Converter) Parse(expression string) (AST, error) {
+	postfix, err := c.ToPostfix(expression)
+	if err != nil {
+		return AST{}, err
+	}
+
+	// Строю дерево по постфиксной форме: операнды добавляю в стек,
+	// а оператор и функция забирают столько последних поддеревьев,
+	// сколько у них операндов
+	s := NewStack[AST]()
+
+	for _, t := range postfix {
+		if t.Kind != Operator && t.Kind != Function {
+			s.Push(AST{Token: t})
+			continue
+		}
+
+		args := make([]AST, t.Arity)
+		for i := len(args) - 1; i >= 0; i-- {
+			args[i] = s.Pop()
+		}
+
+		s.Push(AST{Token: t, Args: args})
+	}
+
+	return s.Pop(), nil
+}
+
+// Postfix возвращает лексемы дерева в постфиксном порядке:
+// сначала операнды, затем оператор.
+func (a AST) Postfix() Tokens {
+	var out Tokens
+
+	for _, arg := range a.Args {
+		out = append(out, arg.Postfix()...)
+	}
+
+	return append(out, a.Token)
+}
+
+// Prefix возвращает лексемы дерева в префиксном порядке:
+// сначала оператор, затем операнды.
+func (a AST) Prefix() Tokens {
+	out := Tokens{a.Token}
+
+	for _, arg := range a.Args {
+		out = append(out, arg.Prefix()...)
+	}
+
+	return out
+}
+
+// String возвращает выражение со скобками вокруг каждого оператора.
+func (a AST) String() string {
+	switch {
+	case a.Token.Kind == Function:
+		args := make([]string, len(a.Args))
+		for i, arg := range a.Args {
+			args[i] = arg.String()
+		}
+
+		return a.Token.Text + "(" + strings.Join(args, ", ") + ")"
+	case len(a.Args) == 0:
+		return a.Token.Text
+	case len(a.Args) == 1 && a.Token.Pos < a.Args[0].Token.Pos:
+		return "(" + a.Token.Text + a.Args[0].String() + ")"
+	case len(a.Args) == 1:
+		return "(" + a.Args[0].String() + a.Token.Text + ")"
+	}
+
+	return "(" + a.Args[0].String() + " " + a.Token.Text + " " + a.Args[1].String() + ")"
+}
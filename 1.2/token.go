@@ -0,0 +1,194 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Kind представляет вид лексемы.
+type Kind int
+
+// Виды лексем.
+const (
+	Number     Kind = iota // Число: 12, 3.5, 1e-3
+	Identifier             // Переменная: x, x1, скорость
+	Operator               // Оператор из таблицы операторов: + - * / ^ !
+	LeftParen              // (
+	RightParen             // )
+	Function               // Имя функции перед скобкой: max в max(a, b)
+	Comma                  // Запятая между аргументами функции
+)
+
+// String возвращает название вида лексемы.
+func (k Kind) String() string {
+	switch k {
+	case Number:
+		return "число"
+	case Identifier:
+		return "переменная"
+	case Operator:
+		return "оператор"
+	case LeftParen:
+		return "открывающая скобка"
+	case RightParen:
+		return "закрывающая скобка"
+	case Function:
+		return "функция"
+	case Comma:
+		return "запятая"
+	}
+
+	return "неизвестная лексема"
+}
+
+// Token представляет лексему выражения.
+type Token struct {
+	Kind Kind
+	Text string
+	Pos  int // Номер первого символа лексемы в выражении, начиная с 0
+
+	// Arity содержит количество операндов оператора или аргументов функции.
+	// Заполняется при переводе в префиксную или постфиксную форму
+	Arity int
+}
+
+// String возвращает текст лексемы. После функции и унарного оператора
+// указывается количество операндов: max@3, -@1.
+func (t Token) String() string {
+	if t.Kind == Function || t.Kind == Operator && t.Arity == 1 {
+		return t.Text + "@" + strconv.Itoa(t.Arity)
+	}
+
+	return t.Text
+}
+
+// Tokens представляет последовательность лексем.
+type Tokens []Token
+
+// String возвращает лексемы через пробел.
+func (ts Tokens) String() string {
+	texts := make([]string, len(ts))
+	for i, t := range ts {
+		texts[i] = t.String()
+	}
+
+	return strings.Join(texts, " ")
+}
+
+// Tokenize разбивает выражение на лексемы с операторами DefaultOperators.
+// Если в выражении есть неизвестный символ, то возвращает *SyntaxError.
+func Tokenize(expression string) (Tokens, error) {
+	return defaultConverter.Tokenize(expression)
+}
+
+// Tokenize разбивает выражение на лексемы.
+// Если в выражении есть неизвестный символ, то возвращает *SyntaxError.
+// Позиции лексем считаются в символах, а не в байтах.
+func (c *Converter) Tokenize(expression string) (Tokens, error) {
+	var tokens Tokens
+
+	runes := []rune(expression)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+			continue
+		case isDigit(r) || r == '.' && i+1 < len(runes) && isDigit(runes[i+1]):
+			end := scanNumber(runes, i)
+			tokens = append(tokens, Token{Kind: Number, Text: string(runes[i:end]), Pos: i})
+			i = end
+			continue
+		case unicode.IsLetter(r) || r == '_':
+			end := i + 1
+			for end < len(runes) && (unicode.IsLetter(runes[end]) || unicode.IsNumber(runes[end]) || runes[end] == '_') {
+				end++
+			}
+
+			// Имя перед открывающей скобкой считаю функцией
+			kind := Identifier
+			if j := skipSpace(runes, end); j < len(runes) && runes[j] == '(' {
+				kind = Function
+			}
+
+			tokens = append(tokens, Token{Kind: kind, Text: string(runes[i:end]), Pos: i})
+			i = end
+			continue
+		}
+
+		switch r {
+		case '(':
+			tokens = append(tokens, Token{Kind: LeftParen, Text: "(", Pos: i})
+			i++
+			continue
+		case ')':
+			tokens = append(tokens, Token{Kind: RightParen, Text: ")", Pos: i})
+			i++
+			continue
+		case ',':
+			tokens = append(tokens, Token{Kind: Comma, Text: ",", Pos: i})
+			i++
+			continue
+		}
+
+		symbol := c.ops.match(runes[i:])
+		if symbol == "" {
+			return nil, syntaxError(i, "неизвестный символ %q", r)
+		}
+
+		tokens = append(tokens, Token{Kind: Operator, Text: symbol, Pos: i})
+		i += len([]rune(symbol))
+	}
+
+	return tokens, nil
+}
+
+// scanNumber возвращает позицию после числа, начинающегося в позиции i:
+// цифры, дробная часть после точки и порядок после e или E.
+func scanNumber(runes []rune, i int) int {
+	digits := func() {
+		for i < len(runes) && isDigit(runes[i]) {
+			i++
+		}
+	}
+
+	digits()
+
+	if i < len(runes) && runes[i] == '.' {
+		i++
+		digits()
+	}
+
+	// Порядок считаю частью числа, только если после e есть цифры,
+	// иначе e станет началом переменной
+	if i < len(runes) && (runes[i] == 'e' || runes[i] == 'E') {
+		j := i + 1
+		if j < len(runes) && (runes[j] == '+' || runes[j] == '-') {
+			j++
+		}
+
+		if j < len(runes) && isDigit(runes[j]) {
+			i = j
+			digits()
+		}
+	}
+
+	return i
+}
+
+// skipSpace возвращает позицию первого непробельного символа, начиная с i.
+func skipSpace(runes []rune, i int) int {
+	for i < len(runes) && unicode.IsSpace(runes[i]) {
+		i++
+	}
+
+	return i
+}
+
+// isDigit возвращает true, если символ является десятичной цифрой.
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
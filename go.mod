@@ -0,0 +1,5 @@
+module github.com/polRk/data_structures_and_algorithms
+
+go 1.24.0
+
+require golang.org/x/text v0.33.0